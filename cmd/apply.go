@@ -0,0 +1,382 @@
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+// applicationsFile records every application sent. It is kept on
+// applicationsRef so that it is shared along with the apply/ tags.
+const (
+	applicationsFile = "applications.json"
+	applicationsRef  = "refs/mycelium/applications"
+)
+
+type application struct {
+	ID      int    `json:"id"`
+	Company string `json:"company"`
+	Role    string `json:"role,omitempty"`
+	Date    string `json:"date"`
+	Status  string `json:"status"`
+	Branch  string `json:"branch,omitempty"`
+	Commit  string `json:"commit"`
+	Tag     string `json:"tag"`
+	PDF     string `json:"pdf,omitempty"`
+	PDFHash string `json:"pdfSha256,omitempty"`
+	Notes   string `json:"notes,omitempty"`
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.AddCommand(applyAddCmd)
+	applyCmd.AddCommand(applyListCmd)
+	applyCmd.AddCommand(applyUpdateCmd)
+	applyCmd.AddCommand(applyShowCmd)
+
+	applyAddCmd.Flags().String("company", "", "Company applied to (defaults to the branch metadata)")
+	applyAddCmd.Flags().String("role", "", "Role applied for (defaults to the branch metadata)")
+	applyAddCmd.Flags().String("date", "today", "Date sent (YYYY-MM-DD or 'today')")
+	applyAddCmd.Flags().String("rev", "HEAD", "Version of the resume that was sent")
+	applyAddCmd.Flags().String("pdf", "", "PDF that was sent (defaults to "+exportFile+" when present)")
+	applyAddCmd.Flags().String("status", "sent", "Status: "+strings.Join(applicationStatuses, ", "))
+	applyAddCmd.Flags().String("notes", "", "Free-form notes")
+
+	applyListCmd.Flags().String("status", "", "Only show applications with this status")
+	applyListCmd.Flags().String("company", "", "Only show applications to this company")
+
+	applyUpdateCmd.Flags().String("status", "", "New status: "+strings.Join(applicationStatuses, ", "))
+	applyUpdateCmd.Flags().String("notes", "", "Replace the notes")
+}
+
+var applyCmd = &cobra.Command{
+	Use:   "apply",
+	Short: "Track which resume version was sent where",
+	Long: `Track applications: which version of the resume was sent to which company
+and when. Every application tags the exact commit (apply/<company>-<date>)
+and remembers the SHA-256 of the PDF that went out.`,
+}
+
+var applyAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Record that a resume version was sent",
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+
+		rev, _ := cmd.Flags().GetString("rev")
+		commit, ok := resolveVersion(r, rev)
+		if !ok {
+			return
+		}
+		app := application{Commit: commit.Hash.String()}
+
+		// 1. Fill in the details, falling back on the branch metadata
+		var meta branchMeta
+		if head, err := r.Head(); err == nil && head.Name().IsBranch() && commit.Hash == head.Hash() {
+			app.Branch = head.Name().Short()
+			meta = metaFor(app.Branch)
+		}
+		if rev == "HEAD" {
+			w, _ := r.Worktree()
+			if dirty, _ := resumeDirty(w); dirty {
+				fmt.Println("[WARN] resume.json has uncommitted changes; the PDF may not match the recorded version.")
+			}
+		}
+		app.Company, _ = cmd.Flags().GetString("company")
+		if app.Company == "" {
+			app.Company = meta.Company
+		}
+		if app.Company == "" {
+			fmt.Println("[ERROR] Name the company with --company, or set it with 'mycelium branch meta --company'.")
+			return
+		}
+		app.Role, _ = cmd.Flags().GetString("role")
+		if app.Role == "" {
+			app.Role = meta.Role
+		}
+		app.Status, _ = cmd.Flags().GetString("status")
+		if !validStatus(app.Status) {
+			fmt.Printf("[ERROR] Unknown status '%s', use one of: %s\n", app.Status, strings.Join(applicationStatuses, ", "))
+			return
+		}
+		app.Notes, _ = cmd.Flags().GetString("notes")
+		date, _ := cmd.Flags().GetString("date")
+		if app.Date, err = parseDate(date); err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+
+		// 2. Fingerprint the PDF that went out
+		pdf, _ := cmd.Flags().GetString("pdf")
+		if pdf == "" {
+			if _, err := os.Stat(exportFile); err == nil {
+				pdf = exportFile
+			}
+		}
+		if pdf != "" {
+			sum, err := fileSHA256(pdf)
+			if err != nil {
+				fmt.Println("[ERROR] Could not read the PDF:", err)
+				return
+			}
+			app.PDF, app.PDFHash = pdf, sum
+		}
+
+		apps, err := loadApplications()
+		if err != nil {
+			fmt.Println("[ERROR] Could not read applications:", err)
+			return
+		}
+		for _, a := range apps {
+			app.ID = max(app.ID, a.ID)
+		}
+		app.ID++
+
+		// 3. Tag the exact version
+		app.Tag, err = applicationTag(r, app)
+		if err != nil {
+			fmt.Println("[ERROR] Could not tag the version:", err)
+			return
+		}
+		_, err = r.CreateTag(app.Tag, commit.Hash, &git.CreateTagOptions{
+			Tagger:  signature(),
+			Message: fmt.Sprintf("Sent to %s on %s", app.Company, app.Date),
+		})
+		if err != nil {
+			fmt.Println("[ERROR] Could not tag the version:", err)
+			return
+		}
+
+		if err := saveApplications(append(apps, app)); err != nil {
+			fmt.Println("[ERROR] Could not save the application:", err)
+			return
+		}
+		syncBranchStatus(app)
+
+		fmt.Printf("[SUCCESS] Application #%d to %s recorded as [%s] (tag %s).\n", app.ID, app.Company, app.Commit[:7], app.Tag)
+		if app.PDFHash == "" {
+			fmt.Println("[INFO] No PDF recorded. Pass --pdf to fingerprint the file you sent.")
+		}
+	},
+}
+
+var applyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List recorded applications",
+	Run: func(cmd *cobra.Command, args []string) {
+		apps, err := loadApplications()
+		if err != nil {
+			fmt.Println("[ERROR] Could not read applications:", err)
+			return
+		}
+		status, _ := cmd.Flags().GetString("status")
+		if status != "" && !validStatus(status) {
+			fmt.Printf("[ERROR] Unknown status '%s', use one of: %s\n", status, strings.Join(applicationStatuses, ", "))
+			return
+		}
+		company, _ := cmd.Flags().GetString("company")
+
+		var shown []application
+		for _, a := range apps {
+			if status != "" && a.Status != status {
+				continue
+			}
+			if company != "" && !strings.EqualFold(a.Company, company) {
+				continue
+			}
+			shown = append(shown, a)
+		}
+		if len(shown) == 0 {
+			fmt.Println("No applications found. Record one with 'mycelium apply add'.")
+			return
+		}
+
+		fmt.Println("📬 APPLICATIONS:")
+		fmt.Println("-------------------")
+		for _, a := range shown {
+			what := a.Company
+			if a.Role != "" {
+				what = a.Role + " at " + a.Company
+			}
+			fmt.Printf("#%-3d %s  %-9s [%s] %s\n", a.ID, a.Date, a.Status, a.Commit[:7], what)
+		}
+	},
+}
+
+var applyUpdateCmd = &cobra.Command{
+	Use:   "update [id]",
+	Short: "Change the status or notes of an application",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apps, i, ok := findApplication(args[0])
+		if !ok {
+			return
+		}
+		app := &apps[i]
+		changed := false
+		if cmd.Flags().Changed("status") {
+			status, _ := cmd.Flags().GetString("status")
+			if !validStatus(status) {
+				fmt.Printf("[ERROR] Unknown status '%s', use one of: %s\n", status, strings.Join(applicationStatuses, ", "))
+				return
+			}
+			app.Status, changed = status, true
+		}
+		if cmd.Flags().Changed("notes") {
+			app.Notes, _ = cmd.Flags().GetString("notes")
+			changed = true
+		}
+		if !changed {
+			fmt.Println("[INFO] Nothing to update. Pass --status or --notes.")
+			return
+		}
+		if err := saveApplications(apps); err != nil {
+			fmt.Println("[ERROR] Could not save the application:", err)
+			return
+		}
+		syncBranchStatus(*app)
+		fmt.Printf("[SUCCESS] Application #%d to %s is now '%s'.\n", app.ID, app.Company, app.Status)
+	},
+}
+
+var applyShowCmd = &cobra.Command{
+	Use:   "show [id]",
+	Short: "Show the details of an application",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		apps, i, ok := findApplication(args[0])
+		if !ok {
+			return
+		}
+		a := apps[i]
+		fmt.Printf("📬 Application #%d\n", a.ID)
+		fmt.Println("-------------------")
+		for _, field := range [][2]string{
+			{"Company", a.Company},
+			{"Role", a.Role},
+			{"Date", a.Date},
+			{"Status", a.Status},
+			{"Branch", a.Branch},
+			{"Version", a.Commit[:7]},
+			{"Tag", a.Tag},
+			{"PDF", a.PDF},
+			{"SHA-256", a.PDFHash},
+			{"Notes", a.Notes},
+		} {
+			if field[1] != "" {
+				fmt.Printf("%-8s %s\n", field[0]+":", field[1])
+			}
+		}
+		if a.PDFHash == "" {
+			return
+		}
+		switch sum, err := fileSHA256(a.PDF); {
+		case err != nil:
+			fmt.Println("[INFO] The PDF is no longer on disk. Restore the tagged version and export it again.")
+		case sum == a.PDFHash:
+			fmt.Println("[INFO] The PDF on disk is the one that was sent.")
+		default:
+			fmt.Println("[WARN] The PDF on disk has changed since it was sent.")
+		}
+	},
+}
+
+func loadApplications() ([]application, error) {
+	var apps []application
+	_, err := loadRecord(applicationsRef, applicationsFile, &apps)
+	return apps, err
+}
+
+func saveApplications(apps []application) error {
+	return saveRecord(applicationsRef, applicationsFile, apps, "Update application log")
+}
+
+// findApplication loads the applications and locates one by id, printing
+// the problem when it cannot.
+func findApplication(arg string) ([]application, int, bool) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil {
+		fmt.Printf("[ERROR] '%s' is not an application number. See 'mycelium apply list'.\n", arg)
+		return nil, 0, false
+	}
+	apps, err := loadApplications()
+	if err != nil {
+		fmt.Println("[ERROR] Could not read applications:", err)
+		return nil, 0, false
+	}
+	for i, a := range apps {
+		if a.ID == id {
+			return apps, i, true
+		}
+	}
+	fmt.Printf("[ERROR] No application #%d. See 'mycelium apply list'.\n", id)
+	return nil, 0, false
+}
+
+// applicationTag names the tag of an application, apply/<company>-<date>,
+// adding a counter when the company got several resumes the same day.
+func applicationTag(r *git.Repository, app application) (string, error) {
+	slug := strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(app.Company), "-"), "-")
+	if slug == "" {
+		slug = "company"
+	}
+	base := fmt.Sprintf("apply/%s-%s", slug, app.Date)
+	name := base
+	for n := 2; ; n++ {
+		_, err := r.Reference(plumbing.NewTagReferenceName(name), false)
+		if err == plumbing.ErrReferenceNotFound {
+			return name, nil
+		}
+		if err != nil {
+			return "", err
+		}
+		name = fmt.Sprintf("%s-%d", base, n)
+	}
+}
+
+// syncBranchStatus mirrors the status of an application onto the branch
+// it was sent from, so 'status' and 'branch list' stay current.
+func syncBranchStatus(app application) {
+	if app.Branch == "" {
+		return
+	}
+	all, err := loadBranchMeta()
+	if err != nil {
+		return
+	}
+	meta, ok := all[app.Branch]
+	if !ok {
+		return
+	}
+	meta.Status = app.Status
+	if meta.Date == "" {
+		meta.Date = app.Date
+	}
+	all[app.Branch] = meta
+	saveBranchMeta(all)
+}
+
+func fileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return sha256Hex(data), nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(blameCmd)
+	blameCmd.Flags().String("rev", "HEAD", "Version to annotate")
+}
+
+var blameCmd = &cobra.Command{
+	Use:   "blame [path]",
+	Short: "Show when and why each field and bullet was last changed",
+	Long: `Annotate every field and bullet of the resume with the version that last
+changed it. History is followed semantically: a bullet that was moved, or a
+job whose entries were reordered, keeps the version that wrote it. Give a
+path such as experience[Acme] to annotate only part of the resume.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		var filter resume.Path
+		if len(args) > 0 {
+			if filter, err = resume.ParsePath(args[0]); err != nil {
+				fmt.Println("[ERROR]", err)
+				return
+			}
+		}
+		rev, _ := cmd.Flags().GetString("rev")
+		tip, ok := resolveVersion(r, rev)
+		if !ok {
+			return
+		}
+
+		origins, err := blame(tip)
+		if err != nil {
+			fmt.Println("[ERROR] Could not walk history:", err)
+			return
+		}
+		tree, err := treeAt(tip)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", rev, err)
+			return
+		}
+		if err := resume.CheckLabels(filter, tree); err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+
+		fmt.Printf("🔎 Blame for %s\n", describeRevision(rev, tip.Hash.String()))
+		fmt.Println("------------------------------------------------------------")
+		var used []*object.Commit
+		seen := map[plumbing.Hash]bool{}
+		for _, leaf := range resume.Leaves(tree) {
+			if filter != nil && !leaf.Path.HasPrefix(filter) {
+				continue
+			}
+			c := origins[leaf.Path.Pointer()]
+			fmt.Printf("[%s] %s  %s: %s\n", c.Hash.String()[:7], c.Author.When.Format("2006-01-02"), leaf.Path, summarize(leaf.Value))
+			if !seen[c.Hash] {
+				seen[c.Hash] = true
+				used = append(used, c)
+			}
+		}
+		if len(used) == 0 {
+			fmt.Println("✨ Nothing to annotate at that path.")
+			return
+		}
+
+		fmt.Println()
+		for _, c := range used {
+			fmt.Printf("[%s] %s\n", c.Hash.String()[:7], firstLine(c.Message))
+		}
+	},
+}
+
+// blame maps the pointer of every leaf of tip's resume to the commit that
+// last changed it. Commits are replayed oldest first; a leaf a commit kept
+// unchanged from one of its parents inherits that parent's answer.
+func blame(tip *object.Commit) (map[string]*object.Commit, error) {
+	order, err := newestFirst([]*object.Commit{tip})
+	if err != nil {
+		return nil, err
+	}
+	commits := map[plumbing.Hash]*object.Commit{}
+	for _, c := range order {
+		commits[c.Hash] = c
+	}
+
+	trees := changeCache{}
+	origins := map[plumbing.Hash]map[string]*object.Commit{}
+	remaining := map[plumbing.Hash]int{}
+	for _, c := range order {
+		for _, p := range c.ParentHashes {
+			remaining[p]++
+		}
+	}
+
+	for i := len(order) - 1; i >= 0; i-- {
+		c := order[i]
+		tree := trees.tree(c)
+		own := map[string]*object.Commit{}
+		for _, leaf := range resume.Leaves(tree) {
+			own[leaf.Path.Pointer()] = c
+		}
+		// Prefer the first parent, as a merge mostly keeps our side
+		for k := len(c.ParentHashes) - 1; k >= 0; k-- {
+			p := c.ParentHashes[k]
+			parent, ok := commits[p]
+			if !ok {
+				continue
+			}
+			for ptr, from := range resume.Trace(trees.tree(parent), tree) {
+				if o, ok := origins[p][from]; ok {
+					own[ptr] = o
+				}
+			}
+		}
+		origins[c.Hash] = own
+
+		// Forget versions no later commit needs
+		for _, p := range c.ParentHashes {
+			if remaining[p]--; remaining[p] == 0 && p != tip.Hash {
+				delete(origins, p)
+				delete(trees, p)
+			}
+		}
+	}
+	return origins[tip.Hash], nil
+}
@@ -2,9 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"mycelium/resume"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +18,17 @@ func init() {
 	rootCmd.AddCommand(branchCmd)
 	branchCmd.AddCommand(branchCreateCmd)
 	branchCmd.AddCommand(branchSwitchCmd)
+	branchCmd.AddCommand(branchListCmd)
+	branchCmd.AddCommand(branchDeleteCmd)
+	branchCmd.AddCommand(branchRenameCmd)
+	branchCmd.AddCommand(branchCompareCmd)
+	branchCmd.AddCommand(branchMetaCmd)
+	branchCreateCmd.Flags().String("from", "", "Start the branch at this revision instead of the current one")
+	addMetaFlags(branchCreateCmd)
+	addMetaFlags(branchMetaCmd)
+	branchMetaCmd.Flags().Bool("clear", false, "Remove all metadata from the branch")
+	branchDeleteCmd.Flags().BoolP("force", "f", false, "Delete even if the branch has changes merged nowhere else")
+	branchSwitchCmd.Flags().Bool("stash", false, "Stash unsaved edits before switching")
 }
 
 var branchCmd = &cobra.Command{
@@ -28,17 +45,48 @@ var branchCreateCmd = &cobra.Command{
 		w, _ := r.Worktree()
 
 		name := args[0]
-		branchRef := plumbing.NewBranchReferenceName(name)
+		var meta branchMeta
+		changed, err := applyMetaFlags(cmd, &meta)
+		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
 
-		err := w.Checkout(&git.CheckoutOptions{
+		branchRef := plumbing.NewBranchReferenceName(name)
+		opts := &git.CheckoutOptions{
 			Branch: branchRef,
 			Create: true,
-		})
+		}
+
+		if from, _ := cmd.Flags().GetString("from"); from != "" {
+			start, ok := resolveVersion(r, from)
+			if !ok {
+				return
+			}
+			if dirty, _ := resumeDirty(w); dirty {
+				fmt.Println("[WARN] Unsaved changes detected.")
+				fmt.Println("[INFO] Run 'mycelium commit' before branching from another version.")
+				return
+			}
+			opts.Hash = start.Hash
+		}
+
+		err = w.Checkout(opts)
 
 		if err != nil {
 			fmt.Println("Error creating branch:", err)
-		} else {
-			fmt.Printf("🌱 Branch '%s' created and active.\n", name)
+			return
+		}
+		fmt.Printf("🌱 Branch '%s' created and active.\n", name)
+
+		if changed {
+			all, _ := loadBranchMeta()
+			all[name] = meta
+			if err := saveBranchMeta(all); err != nil {
+				fmt.Println("[ERROR] Could not save branch metadata:", err)
+				return
+			}
+			fmt.Printf("[INFO] Application: %s\n", meta.summary())
 		}
 	},
 }
@@ -52,14 +100,487 @@ var branchSwitchCmd = &cobra.Command{
 		w, _ := r.Worktree()
 
 		name := args[0]
+		from := ""
+		if head, err := r.Head(); err == nil && head.Name().IsBranch() {
+			from = head.Name().Short()
+		}
+		if _, err := r.Reference(plumbing.NewBranchReferenceName(name), false); err != nil {
+			fmt.Printf("[ERROR] Branch '%s' does not exist.\n", name)
+			return
+		}
+		if from == name {
+			fmt.Printf("[INFO] Already on branch '%s'.\n", name)
+			return
+		}
+
+		// Park unsaved edits rather than lose them to the checkout
+		if dirty, _ := resumeDirty(w); dirty {
+			stash, _ := cmd.Flags().GetBool("stash")
+			if !stash {
+				fmt.Println("[WARN] Unsaved changes detected.")
+				stash = interactive() && confirm("Stash them and switch?")
+			}
+			if !stash {
+				fmt.Println("[INFO] Run 'mycelium commit' or 'mycelium stash' first, or switch with --stash.")
+				return
+			}
+			hash, err := stashPush(r, "auto-stash before switching to "+name)
+			if err != nil {
+				fmt.Println("[ERROR] Stash failed:", err)
+				return
+			}
+			fmt.Printf("📦 Edits stashed as stash@{0} [%s]. Run 'mycelium stash pop' to bring them back.\n", hash)
+		}
+
 		err := w.Checkout(&git.CheckoutOptions{
 			Branch: plumbing.NewBranchReferenceName(name),
 		})
 
 		if err != nil {
 			fmt.Printf("[ERROR] Branch '%s' does not exist.\n", name)
+			return
+		}
+		if from != "" {
+			record(journalEntry{
+				Action:  "switch",
+				Summary: fmt.Sprintf("switched from '%s' to '%s'", from, name),
+				Branch:  from,
+				Target:  name,
+			})
+		}
+		fmt.Printf("🔄 Switched to branch '%s'.\n", name)
+	},
+}
+
+var branchListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List resume branches with their latest version",
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		branches, err := listBranches(r)
+		if err != nil || len(branches) == 0 {
+			fmt.Println("No branches found yet. Commit once first.")
+			return
+		}
+		current := ""
+		if head, err := r.Head(); err == nil {
+			current = head.Name().Short()
+		}
+
+		meta, _ := loadBranchMeta()
+		width := 0
+		for _, b := range branches {
+			width = max(width, len(b.Name().Short()))
+		}
+		fmt.Println("🌿 BRANCHES:")
+		fmt.Println("-------------------")
+		for _, b := range branches {
+			mark := " "
+			if b.Name().Short() == current {
+				mark = "*"
+			}
+			c, err := r.CommitObject(b.Hash())
+			if err != nil {
+				continue
+			}
+			fmt.Printf("%s %-*s [%s] %s (%s)\n", mark, width, b.Name().Short(), c.Hash.String()[:7], firstLine(c.Message), c.Author.When.Format("2006-01-02"))
+			if m := meta[b.Name().Short()]; !m.empty() {
+				fmt.Printf("  %-*s 🏢 %s\n", width, "", m.summary())
+			}
+		}
+	},
+}
+
+var branchDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a resume branch",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		name := args[0]
+		ref, err := r.Reference(plumbing.NewBranchReferenceName(name), false)
+		if err != nil {
+			fmt.Printf("[ERROR] Branch '%s' does not exist.\n", name)
+			return
+		}
+		if head, err := r.Head(); err == nil && head.Name() == ref.Name() {
+			fmt.Printf("[ERROR] Cannot delete '%s' while it is active. Switch to another branch first.\n", name)
+			return
+		}
+
+		// Refuse to lose versions no other branch holds
+		if force, _ := cmd.Flags().GetBool("force"); !force {
+			tip, _ := r.CommitObject(ref.Hash())
+			unmerged, err := unmergedCommits(r, tip, ref.Name())
+			if err != nil {
+				fmt.Println("[ERROR] Could not inspect the branch:", err)
+				return
+			}
+			if len(unmerged) > 0 {
+				fmt.Printf("[WARN] Branch '%s' has %d version(s) that no other branch contains:\n", name, len(unmerged))
+				for _, c := range unmerged {
+					fmt.Printf("  [%s] %s\n", c.Hash.String()[:7], firstLine(c.Message))
+				}
+				fmt.Println("[INFO] Sync them into another branch first, or use -f to delete anyway.")
+				return
+			}
+		}
+
+		if err := r.Storer.RemoveReference(ref.Name()); err != nil {
+			fmt.Println("[ERROR] Could not delete branch:", err)
+			return
+		}
+		if meta, err := loadBranchMeta(); err == nil {
+			if _, ok := meta[name]; ok {
+				delete(meta, name)
+				saveBranchMeta(meta)
+			}
+		}
+		fmt.Printf("🗑️  Branch '%s' deleted (was [%s]).\n", name, ref.Hash().String()[:7])
+	},
+}
+
+var branchRenameCmd = &cobra.Command{
+	Use:   "rename [old] [new]",
+	Short: "Rename a resume branch",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		oldName, newName := args[0], args[1]
+		ref, err := r.Reference(plumbing.NewBranchReferenceName(oldName), false)
+		if err != nil {
+			fmt.Printf("[ERROR] Branch '%s' does not exist.\n", oldName)
+			return
+		}
+		newRef := plumbing.NewBranchReferenceName(newName)
+		if err := newRef.Validate(); err != nil {
+			fmt.Printf("[ERROR] '%s' is not a valid branch name.\n", newName)
+			return
+		}
+		if _, err := r.Reference(newRef, false); err == nil {
+			fmt.Printf("[ERROR] Branch '%s' already exists.\n", newName)
+			return
+		}
+
+		if err := r.Storer.SetReference(plumbing.NewHashReference(newRef, ref.Hash())); err != nil {
+			fmt.Println("[ERROR] Could not rename branch:", err)
+			return
+		}
+		// Keep HEAD on the branch when renaming the active one
+		if head, err := r.Storer.Reference(plumbing.HEAD); err == nil && head.Target() == ref.Name() {
+			r.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, newRef))
+		}
+		r.Storer.RemoveReference(ref.Name())
+		if meta, err := loadBranchMeta(); err == nil {
+			if m, ok := meta[oldName]; ok {
+				delete(meta, oldName)
+				meta[newName] = m
+				saveBranchMeta(meta)
+			}
+		}
+		fmt.Printf("🏷️  Branch '%s' renamed to '%s'.\n", oldName, newName)
+	},
+}
+
+var branchCompareCmd = &cobra.Command{
+	Use:   "compare [a] [b]",
+	Short: "Show how two branches have diverged",
+	Long: `Show how two branches have diverged since their common ancestor: how many
+versions each has that the other lacks, what each changed in the resume,
+and whether syncing them would conflict.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		a, b := args[0], args[1]
+		ca, err := resolveCommit(r, a)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not find branch [%s].\n", a)
+			return
+		}
+		cb, err := resolveCommit(r, b)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not find branch [%s].\n", b)
+			return
+		}
+		bases, err := ca.MergeBase(cb)
+		if err != nil || len(bases) == 0 {
+			fmt.Printf("[ERROR] %s and %s share no history.\n", a, b)
+			return
+		}
+		base := bases[0]
+
+		ahead, err := commitsSince(ca, base)
+		if err != nil {
+			fmt.Println("[ERROR] Could not walk history:", err)
+			return
+		}
+		behind, err := commitsSince(cb, base)
+		if err != nil {
+			fmt.Println("[ERROR] Could not walk history:", err)
+			return
+		}
+
+		fmt.Printf("🔀 Comparing %s with %s\n", a, b)
+		fmt.Println("------------------------------------------------------------")
+		fmt.Printf("Common ancestor: [%s] %s\n", base.Hash.String()[:7], firstLine(base.Message))
+		fmt.Printf("%s is %d version(s) ahead and %d version(s) behind %s.\n", a, ahead, behind, b)
+		if ahead == 0 && behind == 0 {
+			fmt.Println("✨ Both branches point at the same version.")
+			return
+		}
+
+		// Summarize what each side changed since they split
+		var trees [3]interface{}
+		for i, c := range []*object.Commit{base, ca, cb} {
+			trees[i], err = treeAt(c)
+			if err != nil {
+				fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", c.Hash.String()[:7], err)
+				return
+			}
+		}
+		palette := newPalette(false)
+		for i, name := range []string{a, b} {
+			changes := resume.DiffTrees(trees[0], trees[i+1])
+			fmt.Printf("\nOnly on %s (%d change(s)):\n", name, len(changes))
+			for _, c := range changes {
+				printChange(c, palette)
+			}
+		}
+
+		_, conflicts := resume.MergeTrees(trees[0], trees[1], trees[2], nil)
+		fmt.Println()
+		if len(conflicts) == 0 {
+			fmt.Println("[INFO] Syncing these branches would merge cleanly.")
+			return
+		}
+		paths := make([]string, len(conflicts))
+		for i, c := range conflicts {
+			paths[i] = c.Path.String()
+		}
+		fmt.Printf("[WARN] Syncing these branches would conflict in %d field(s): %s\n", len(conflicts), strings.Join(paths, ", "))
+	},
+}
+
+var branchMetaCmd = &cobra.Command{
+	Use:   "meta [name]",
+	Short: "Show or edit the application a branch represents",
+	Long: `Show or edit the application a branch represents: the company, the role,
+the job description, the application date and its status (drafted, sent,
+interview, rejected or offer). Without a name the active branch is used,
+and without flags the current metadata is shown.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		var name string
+		if len(args) > 0 {
+			name = args[0]
+			if _, err := r.Reference(plumbing.NewBranchReferenceName(name), false); err != nil {
+				fmt.Printf("[ERROR] Branch '%s' does not exist.\n", name)
+				return
+			}
 		} else {
-			fmt.Printf("🔄 Switched to branch '%s'.\n", name)
+			head, err := r.Head()
+			if err != nil || !head.Name().IsBranch() {
+				fmt.Println("[ERROR] No active branch. Name the branch: mycelium branch meta <name>")
+				return
+			}
+			name = head.Name().Short()
+		}
+
+		all, err := loadBranchMeta()
+		if err != nil {
+			fmt.Println("[ERROR] Could not read branch metadata:", err)
+			return
+		}
+		meta := all[name]
+
+		if clear, _ := cmd.Flags().GetBool("clear"); clear {
+			delete(all, name)
+			if err := saveBranchMeta(all); err != nil {
+				fmt.Println("[ERROR] Could not save branch metadata:", err)
+				return
+			}
+			fmt.Printf("[SUCCESS] Metadata of '%s' cleared.\n", name)
+			return
+		}
+
+		changed, err := applyMetaFlags(cmd, &meta)
+		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+		if changed {
+			all[name] = meta
+			if err := saveBranchMeta(all); err != nil {
+				fmt.Println("[ERROR] Could not save branch metadata:", err)
+				return
+			}
+			fmt.Printf("[SUCCESS] Metadata of '%s' updated.\n", name)
+		}
+
+		fmt.Printf("📇 Branch '%s'\n", name)
+		fmt.Println("-------------------")
+		if meta.empty() {
+			fmt.Println("No metadata yet. Add some with --company, --role, --job-file, --date or --status.")
+			return
+		}
+		for _, field := range [][2]string{
+			{"Company", meta.Company},
+			{"Role", meta.Role},
+			{"Status", meta.Status},
+			{"Date", meta.Date},
+		} {
+			if field[1] != "" {
+				fmt.Printf("%-8s %s\n", field[0]+":", field[1])
+			}
+		}
+		if meta.Job != "" {
+			fmt.Println("\nJob description:")
+			fmt.Println(meta.Job)
 		}
 	},
 }
+
+func addMetaFlags(cmd *cobra.Command) {
+	cmd.Flags().String("company", "", "Company the application is for")
+	cmd.Flags().String("role", "", "Role title applied for")
+	cmd.Flags().String("job", "", "Job description text")
+	cmd.Flags().String("job-file", "", "Read the job description from a file")
+	cmd.Flags().String("date", "", "Application date (YYYY-MM-DD or 'today')")
+	cmd.Flags().String("status", "", "Application status: "+strings.Join(applicationStatuses, ", "))
+}
+
+// applyMetaFlags copies the metadata flags the user set into meta and
+// reports whether any were set.
+func applyMetaFlags(cmd *cobra.Command, meta *branchMeta) (bool, error) {
+	flags := cmd.Flags()
+	if flags.Changed("status") {
+		status, _ := flags.GetString("status")
+		if !validStatus(status) {
+			return false, fmt.Errorf("unknown status '%s', use one of: %s", status, strings.Join(applicationStatuses, ", "))
+		}
+		meta.Status = status
+	}
+	if flags.Changed("date") {
+		date, _ := flags.GetString("date")
+		date, err := parseDate(date)
+		if err != nil {
+			return false, err
+		}
+		meta.Date = date
+	}
+	if flags.Changed("job-file") {
+		path, _ := flags.GetString("job-file")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return false, fmt.Errorf("could not read the job description: %v", err)
+		}
+		meta.Job = strings.TrimSpace(string(data))
+	}
+	if flags.Changed("job") {
+		meta.Job, _ = flags.GetString("job")
+	}
+	if flags.Changed("company") {
+		meta.Company, _ = flags.GetString("company")
+	}
+	if flags.Changed("role") {
+		meta.Role, _ = flags.GetString("role")
+	}
+	for _, name := range []string{"company", "role", "job", "job-file", "date", "status"} {
+		if flags.Changed(name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// listBranches returns the local branches sorted by name.
+func listBranches(r *git.Repository) ([]*plumbing.Reference, error) {
+	iter, err := r.Branches()
+	if err != nil {
+		return nil, err
+	}
+	var refs []*plumbing.Reference
+	iter.ForEach(func(ref *plumbing.Reference) error {
+		refs = append(refs, ref)
+		return nil
+	})
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name().Short() < refs[j].Name().Short() })
+	return refs, nil
+}
+
+// unmergedCommits lists the commits reachable from tip that no branch
+// other than self can reach.
+func unmergedCommits(r *git.Repository, tip *object.Commit, self plumbing.ReferenceName) ([]*object.Commit, error) {
+	branches, err := listBranches(r)
+	if err != nil {
+		return nil, err
+	}
+	reachable := map[plumbing.Hash]bool{}
+	for _, b := range branches {
+		if b.Name() == self {
+			continue
+		}
+		c, err := r.CommitObject(b.Hash())
+		if err != nil {
+			return nil, err
+		}
+		err = object.NewCommitPreorderIter(c, reachable, nil).ForEach(func(c *object.Commit) error {
+			reachable[c.Hash] = true
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	var out []*object.Commit
+	err = object.NewCommitPreorderIter(tip, reachable, nil).ForEach(func(c *object.Commit) error {
+		out = append(out, c)
+		return nil
+	})
+	return out, err
+}
+
+// commitsSince counts the commits reachable from tip but not from base.
+func commitsSince(tip, base *object.Commit) (int, error) {
+	seen := map[plumbing.Hash]bool{}
+	err := object.NewCommitPreorderIter(base, nil, nil).ForEach(func(c *object.Commit) error {
+		seen[c.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	err = object.NewCommitPreorderIter(tip, seen, nil).ForEach(func(c *object.Commit) error {
+		n++
+		return nil
+	})
+	return n, err
+}
+
+// firstLine returns the subject of a commit message.
+func firstLine(msg string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	return line
+}
@@ -2,10 +2,12 @@ package cmd
 
 import (
 	"fmt"
-	"time"
+	"os"
+
+	"mycelium/resume"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/spf13/cobra"
 )
 
@@ -13,6 +15,8 @@ func init() {
 	// THIS LINE IS CRITICAL - it connects "commit" to the main tool
 	rootCmd.AddCommand(commitCmd)
 	commitCmd.Flags().StringP("message", "m", "", "Commit message")
+	commitCmd.Flags().Bool("no-verify", false, "Skip the schema check before committing")
+	commitCmd.Flags().Bool("amend", false, "Replace the last version instead of adding a new one")
 }
 
 var commitCmd = &cobra.Command{
@@ -20,7 +24,8 @@ var commitCmd = &cobra.Command{
 	Short: "Save current state of the resume",
 	Run: func(cmd *cobra.Command, args []string) {
 		msg, _ := cmd.Flags().GetString("message")
-		if msg == "" {
+		amend, _ := cmd.Flags().GetBool("amend")
+		if msg == "" && !amend {
 			fmt.Println("[ERROR] Please provide a commit message: mycelium commit -m 'Updated skills'")
 			return
 		}
@@ -34,27 +39,71 @@ var commitCmd = &cobra.Command{
 
 		w, _ := r.Worktree()
 
-		// 1. Add resume.json
+		// Commits made on a detached HEAD belong to no branch and get lost
+		if head, err := r.Head(); err == nil && !head.Name().IsBranch() {
+			fmt.Printf("[ERROR] No active branch: you are viewing version [%s].\n", head.Hash().String()[:7])
+			fmt.Println("[INFO] Run 'mycelium branch create <name>' to keep working from here, or switch to a branch.")
+			return
+		}
+		before := headHash(r)
+
+		opts := &git.CommitOptions{Author: signature()}
+		if amend {
+			if before == "" {
+				fmt.Println("[ERROR] There is no version to amend yet.")
+				return
+			}
+			last, err := r.CommitObject(plumbing.NewHash(before))
+			if err != nil {
+				fmt.Println("Error reading the last version:", err)
+				return
+			}
+			if msg == "" {
+				msg = last.Message
+			}
+			// A synced version keeps both of its parents. Its content can match
+			// the first parent's, which go-git would otherwise take as empty.
+			opts.Amend = len(last.ParentHashes) < 2
+			if !opts.Amend {
+				opts.Parents = last.ParentHashes
+				opts.AllowEmptyCommits = true
+			}
+		}
+
+		// 1. Refuse to save a resume that does not match the schema
+		if noVerify, _ := cmd.Flags().GetBool("no-verify"); !noVerify {
+			data, err := os.ReadFile(resume.FileName)
+			if err != nil {
+				fmt.Println("Error reading resume.json:", err)
+				return
+			}
+			if !reportViolations(resume.FileName, data) {
+				fmt.Println("[INFO] Fix the errors above, or use --no-verify to commit anyway.")
+				os.Exit(1)
+			}
+		}
+
+		// 2. Add resume.json
 		_, err = w.Add("resume.json")
 		if err != nil {
 			fmt.Println("Error staging file:", err)
 			return
 		}
 
-		// 2. Commit
-		commit, err := w.Commit(msg, &git.CommitOptions{
-			Author: &object.Signature{
-				Name:  "mycelium User",
-				Email: "user@mycelium.local",
-				When:  time.Now(),
-			},
-		})
+		// 3. Commit
+		commit, err := w.Commit(msg, opts)
 
 		if err != nil {
 			fmt.Println("Error committing:", err)
 			return
 		}
 
+		if amend {
+			recordMove(r, "amend", fmt.Sprintf("[%s] amended as [%s] %s", before[:7], commit.String()[:7], firstLine(msg)), before)
+			fmt.Printf("[SUCCESS] Version Amended! [%s] %s\n", commit.String()[:7], firstLine(msg))
+			return
+		}
+		recordMove(r, "commit", fmt.Sprintf("[%s] %s", commit.String()[:7], msg), before)
 		fmt.Printf("[SUCCESS] Version Saved! [%s] %s\n", commit.String()[:7], msg)
 	},
 }
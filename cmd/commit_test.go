@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func TestAmendSyncedVersionMessage(t *testing.T) {
+	r, hashes := testRepo(t, "v1", "v2")
+	ours, _ := r.CommitObject(hashes[1])
+	side, _ := r.CommitObject(hashes[0])
+	theirs, err := commitFile(r, side, []byte("{}\n"), "side")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A sync that kept our content: the tree matches the first parent's
+	parents := []plumbing.Hash{ours.Hash, theirs}
+	synced, err := writeCommit(r, &object.Commit{
+		Author:       *signature(),
+		Committer:    *signature(),
+		Message:      "Sync master with side",
+		TreeHash:     ours.TreeHash,
+		ParentHashes: parents,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	head, _ := r.Head()
+	if err := r.Storer.SetReference(plumbing.NewHashReference(head.Name(), synced)); err != nil {
+		t.Fatal(err)
+	}
+
+	commitCmd.Flags().Set("amend", "true")
+	commitCmd.Flags().Set("message", "Sync with the side branch")
+	t.Cleanup(func() {
+		commitCmd.Flags().Set("amend", "false")
+		commitCmd.Flags().Set("message", "")
+	})
+	commitCmd.Run(commitCmd, nil)
+
+	head, _ = r.Head()
+	if head.Hash() == synced {
+		t.Fatal("amend did not replace the synced version")
+	}
+	amended, _ := r.CommitObject(head.Hash())
+	if strings.TrimSpace(amended.Message) != "Sync with the side branch" {
+		t.Errorf("message = %q", amended.Message)
+	}
+	if !reflect.DeepEqual(amended.ParentHashes, parents) {
+		t.Errorf("parents = %v, want %v", amended.ParentHashes, parents)
+	}
+	if amended.TreeHash != ours.TreeHash {
+		t.Error("amend changed the content")
+	}
+}
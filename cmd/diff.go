@@ -4,128 +4,336 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"reflect"
+	"strings"
+
+	"mycelium/resume"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/spf13/cobra"
 )
 
-// Fully mapped structs for the diff engine
-type DiffResume struct {
-	Basics     DiffBasics       `json:"basics"`
-	Education  []DiffEducation  `json:"education"`
-	Experience []DiffExperience `json:"experience"`
-	Skills     DiffSkills       `json:"skills"`
-}
-
-type DiffBasics struct {
-	Name  string `json:"name"`
-	Email string `json:"email"`
-	Phone string `json:"phone"`
-}
-
-type DiffEducation struct {
-	School string `json:"school"`
-	Degree string `json:"degree"`
-}
-
-type DiffExperience struct {
-	Company  string   `json:"company"`
-	Role     string   `json:"role"`
-	Location string   `json:"location"`
-	Date     string   `json:"date"`
-	Points   []string `json:"points"`
-}
-
-type DiffSkills struct {
-	Languages []string `json:"languages"`
-	Tools     []string `json:"tools"`
-}
-
 func init() {
 	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().Bool("plain", false, "No colors; mark reworded text as [-removed-]{+added+}")
+	diffCmd.Flags().String("format", "text", "Output format: text, json or jsonpatch")
 }
 
 var diffCmd = &cobra.Command{
-	Use:   "diff",
+	Use:   "diff [rev-a] [rev-b]",
 	Short: "Show all changes in your resume data",
+	Long: `Show a semantic diff of resume.json.
+
+With no arguments the working file is compared with HEAD. With one revision
+the working file is compared with that revision, and with two revisions the
+first is compared with the second. Revisions may be hashes, branch names or
+tags.`,
+	Args: cobra.MaximumNArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		// 1. Read Current from disk
-		data, _ := os.ReadFile("resume.json")
-		var current DiffResume
-		json.Unmarshal(data, &current)
-
-		// 2. Read Previous from Git
-		r, _ := git.PlainOpen(".")
-		ref, err := r.Head()
+		format, _ := cmd.Flags().GetString("format")
+		if format != "text" && format != "json" && format != "jsonpatch" {
+			fmt.Printf("[ERROR] Unknown format '%s'. Use text, json or jsonpatch.\n", format)
+			return
+		}
+
+		r, err := git.PlainOpen(".")
 		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+
+		// 1. Read the older side from Git
+		base := "HEAD"
+		if len(args) > 0 {
+			base = args[0]
+		}
+		if _, err := r.Head(); err != nil && len(args) == 0 {
 			fmt.Println("[ERROR] No commit history found. Commit once first.")
 			return
 		}
-		commit, _ := r.CommitObject(ref.Hash())
-		tree, _ := commit.Tree()
-		file, _ := tree.File("resume.json")
-		prevData, _ := file.Contents()
-		var prev DiffResume
-		json.Unmarshal([]byte(prevData), &prev)
+		commit, ok := resolveVersion(r, base)
+		if !ok {
+			return
+		}
+		prev, err := readResumeAt(commit)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", base, err)
+			return
+		}
 
-		fmt.Printf("🔍 Diffing current changes against: %s\n", ref.Hash().String()[:7])
-		fmt.Println("------------------------------------------------------------")
-		hasChanges := false
+		// 2. Read the newer side from Git or from disk
+		var current *resume.Resume
+		target := "working copy"
+		if len(args) == 2 {
+			other, ok := resolveVersion(r, args[1])
+			if !ok {
+				return
+			}
+			current, err = readResumeAt(other)
+			if err != nil {
+				fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", args[1], err)
+				return
+			}
+			target = describeRevision(args[1], other.Hash.String())
+		} else {
+			current, err = resume.Load(resume.FileName)
+			if err != nil {
+				fmt.Println("[ERROR] Could not read resume.json:", err)
+				return
+			}
+		}
 
-		// --- CHECK BASICS ---
-		if prev.Basics.Name != current.Basics.Name {
-			fmt.Printf("[BASICS] Name: %s -> %s\n", prev.Basics.Name, current.Basics.Name)
-			hasChanges = true
+		// 3. Walk both trees
+		oldTree, err := resume.Tree(prev)
+		if err != nil {
+			fmt.Println("[ERROR] Diff failed:", err)
+			return
 		}
-		if prev.Basics.Phone != current.Basics.Phone {
-			fmt.Printf("[BASICS] Phone: %s -> %s\n", prev.Basics.Phone, current.Basics.Phone)
-			hasChanges = true
+		newTree, err := resume.Tree(current)
+		if err != nil {
+			fmt.Println("[ERROR] Diff failed:", err)
+			return
 		}
+		changes := resume.DiffTrees(oldTree, newTree)
 
-		// --- CHECK EXPERIENCE (Comprehensive) ---
-		if len(prev.Experience) != len(current.Experience) {
-			fmt.Printf("[EXP] Number of jobs changed: %d -> %d\n", len(prev.Experience), len(current.Experience))
-			hasChanges = true
-		} else {
-			for i := range current.Experience {
-				p, c := prev.Experience[i], current.Experience[i]
-				if p.Company != c.Company {
-					fmt.Printf("[EXP] Company: %s -> %s\n", p.Company, c.Company)
-					hasChanges = true
-				}
-				if p.Role != c.Role {
-					fmt.Printf("[EXP] Role at %s: %s -> %s\n", c.Company, p.Role, c.Role)
-					hasChanges = true
-				}
-				if !reflect.DeepEqual(p.Points, c.Points) {
-					fmt.Printf("[EXP] Bullet points updated for %s\n", c.Company)
-					hasChanges = true
-				}
+		switch format {
+		case "json":
+			if changes == nil {
+				changes = []resume.Change{}
 			}
+			printJSON(changes)
+			return
+		case "jsonpatch":
+			ops := resume.Patch(oldTree, newTree)
+			if ops == nil {
+				ops = []resume.Operation{}
+			}
+			printJSON(ops)
+			return
 		}
 
-		// --- CHECK EDUCATION ---
-		if len(prev.Education) != len(current.Education) {
-			fmt.Printf("[EDU] Schools changed.\n")
-			hasChanges = true
+		if len(args) < 2 {
+			fmt.Printf("🔍 Diffing current changes against: %s\n", describeRevision(base, commit.Hash.String()))
 		} else {
-			for i := range current.Education {
-				if prev.Education[i].School != current.Education[i].School {
-					fmt.Printf("[EDU] School: %s -> %s\n", prev.Education[i].School, current.Education[i].School)
-					hasChanges = true
-				}
+			fmt.Printf("🔍 Diffing %s -> %s\n", describeRevision(base, commit.Hash.String()), target)
+		}
+		fmt.Println("------------------------------------------------------------")
+		if len(changes) == 0 {
+			if len(args) < 2 {
+				fmt.Println("✨ No changes found. Your JSON on disk matches the Git history.")
+			} else {
+				fmt.Println("✨ No changes found. Both versions hold the same resume.")
 			}
+			return
 		}
+		plain, _ := cmd.Flags().GetBool("plain")
+		printChanges(changes, newPalette(plain))
+	},
+}
 
-		// --- CHECK SKILLS ---
-		if !reflect.DeepEqual(prev.Skills.Languages, current.Skills.Languages) {
-			fmt.Println("[SKILLS] Languages list was modified.")
-			hasChanges = true
+// describeRevision shows a revision as the user typed it plus its short
+// hash, or just the short hash when the user typed one.
+func describeRevision(rev, hash string) string {
+	if rev == "HEAD" || strings.HasPrefix(hash, rev) {
+		return hash[:7]
+	}
+	return fmt.Sprintf("%s (%s)", rev, hash[:7])
+}
+
+// printJSON writes v to stdout as indented JSON.
+func printJSON(v interface{}) {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		fmt.Fprintln(os.Stderr, "[ERROR]", err)
+	}
+}
+
+// sectionTags are the short labels printed in front of each change.
+var sectionTags = map[string]string{
+	"basics":       "BASICS",
+	"education":    "EDU",
+	"experience":   "EXP",
+	"projects":     "PROJ",
+	"skills":       "SKILLS",
+	"sectionOrder": "ORDER",
+
+	"awards":         "AWARD",
+	"publications":   "PUB",
+	"volunteering":   "VOL",
+	"certifications": "CERT",
+}
+
+func printChanges(changes []resume.Change, p palette) {
+	for _, c := range changes {
+		printChange(c, p)
+	}
+	fmt.Printf("\n%d change(s) found.\n", len(changes))
+}
+
+func printChange(c resume.Change, p palette) {
+	if isBullet(c.Path) {
+		fmt.Printf("[%s] %s\n", changeTag(c.Path), describeBullet(c, p))
+		return
+	}
+	fmt.Printf("[%s] %s\n", changeTag(c.Path), describeChange(c))
+}
+
+// palette highlights inserted and deleted text, either with terminal
+// colors or, in plain mode, with word-diff style markers.
+type palette struct {
+	color bool
+}
+
+func newPalette(plain bool) palette {
+	if plain || os.Getenv("NO_COLOR") != "" {
+		return palette{}
+	}
+	info, err := os.Stdout.Stat()
+	return palette{color: err == nil && info.Mode()&os.ModeCharDevice != 0}
+}
+
+func (p palette) inserted(s string) string {
+	if p.color {
+		return "\x1b[32m" + s + "\x1b[0m"
+	}
+	return "{+" + s + "+}"
+}
+
+func (p palette) deleted(s string) string {
+	if p.color {
+		return "\x1b[31;9m" + s + "\x1b[0m"
+	}
+	return "[-" + s + "-]"
+}
+
+// isBullet reports whether a path addresses one bullet point.
+func isBullet(p resume.Path) bool {
+	return len(p) >= 2 && p[len(p)-1].IsIndex() && p[len(p)-2].Key == "points"
+}
+
+func describeBullet(c resume.Change, p palette) string {
+	where := c.Path[:len(c.Path)-1].String()
+	pos := c.Path[len(c.Path)-1].Index + 1
+	switch c.Kind {
+	case resume.Added:
+		return fmt.Sprintf("Added bullet %d in %s:\n      %s", pos, where, p.inserted(summarize(c.New)))
+	case resume.Removed:
+		return fmt.Sprintf("Removed bullet %d in %s:\n      %s", pos, where, p.deleted(summarize(c.Old)))
+	case resume.Moved:
+		return fmt.Sprintf("Moved bullet in %s from position %d to %d: %s", where, c.From+1, c.To+1, summarize(c.New))
+	}
+
+	oldText, _ := c.Old.(string)
+	newText, _ := c.New.(string)
+	var b strings.Builder
+	spans := resume.WordDiff(oldText, newText)
+	for i, span := range spans {
+		// A replacement reads as one unit: [-old-]{+new+}
+		if i > 0 && !(span.Kind == resume.Inserted && spans[i-1].Kind == resume.Deleted) {
+			b.WriteByte(' ')
+		}
+		switch span.Kind {
+		case resume.Inserted:
+			b.WriteString(p.inserted(span.Text))
+		case resume.Deleted:
+			b.WriteString(p.deleted(span.Text))
+		default:
+			b.WriteString(span.Text)
 		}
+	}
+	return fmt.Sprintf("Reworded bullet %d in %s:\n      %s", pos, where, b.String())
+}
+
+func changeTag(p resume.Path) string {
+	if len(p) == 0 {
+		return "RESUME"
+	}
+	if tag, ok := sectionTags[p[0].Key]; ok {
+		return tag
+	}
+	return strings.ToUpper(p[0].Key)
+}
+
+// entryNouns name the entries of list sections in change descriptions.
+var entryNouns = map[string]string{
+	"experience":   "job",
+	"education":    "school",
+	"projects":     "project",
+	"points":       "bullet",
+	"sectionOrder": "section",
 
-		if !hasChanges {
-			fmt.Println("✨ No changes found. Your JSON on disk matches the Git history.")
+	"awards":         "award",
+	"publications":   "publication",
+	"volunteering":   "role",
+	"certifications": "certification",
+}
+
+func describeChange(c resume.Change) string {
+	what := c.Path.String()
+	if noun := entryNoun(c.Path); noun != "" && c.Kind != resume.Modified {
+		v := c.New
+		if c.Kind == resume.Removed {
+			v = c.Old
 		}
-	},
+		what = fmt.Sprintf("%s %s", noun, summarize(v))
+		if parent := c.Path[:len(c.Path)-1]; len(parent) > 1 {
+			what += " in " + parent.String()
+		}
+	}
+
+	switch c.Kind {
+	case resume.Added:
+		if last := c.Path[len(c.Path)-1]; last.IsIndex() {
+			return fmt.Sprintf("Added %s at position %d", what, last.Index+1)
+		}
+		return fmt.Sprintf("Added %s", what)
+	case resume.Removed:
+		return fmt.Sprintf("Removed %s", what)
+	case resume.Moved:
+		if !c.Path[len(c.Path)-1].IsIndex() {
+			what = fmt.Sprintf("%s (%s)", what, summarize(c.New))
+		}
+		return fmt.Sprintf("Moved %s from position %d to %d", what, c.From+1, c.To+1)
+	default:
+		return fmt.Sprintf("%s: %s -> %s", what, summarize(c.Old), summarize(c.New))
+	}
+}
+
+// entryNoun returns the noun for a path that addresses a whole list entry.
+func entryNoun(p resume.Path) string {
+	if len(p) < 2 || !p[len(p)-1].IsIndex() {
+		return ""
+	}
+	if noun, ok := entryNouns[p[len(p)-2].Key]; ok {
+		return noun
+	}
+	return "entry"
+}
+
+// summarize renders a tree value on a single line.
+func summarize(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		if val == "" {
+			return `""`
+		}
+		return val
+	case []interface{}:
+		return fmt.Sprintf("[%d items]", len(val))
+	case *resume.Object:
+		var parts []string
+		for _, k := range []string{"company", "school", "name", "title", "role", "degree"} {
+			if s, ok := val.Values[k].(string); ok && s != "" {
+				parts = append(parts, s)
+			}
+		}
+		if len(parts) == 0 {
+			return fmt.Sprintf("{%d fields}", len(val.Keys))
+		}
+		return strings.Join(parts, " - ")
+	}
+	return fmt.Sprint(v)
 }
@@ -1,52 +1,147 @@
 package cmd
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"html/template"
 	"io"
 	"net/http"
-	"os"
 
+	"mycelium/render"
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().StringP("template", "t", render.DefaultTemplate, "Theme to preview, as used by export")
 }
 
 var editCmd = &cobra.Command{
 	Use:   "edit",
 	Short: "Open the Mycelium Live Form Editor",
 	Run: func(cmd *cobra.Command, args []string) {
-		http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-			data, err := os.ReadFile("resume.json")
-			if err != nil {
-				http.Error(w, "[ERROR] resume.json not found. Run 'mycelium init' first.", 404)
-				return
-			}
-			tmpl, _ := template.New("editor").Parse(editorHTML)
+		name, _ := cmd.Flags().GetString("template")
+		serveEditor(name)
+	},
+}
+
+// serveEditor runs the form editor, previewing through the named export
+// template. When a sync is waiting on conflicts the editor also offers
+// them for resolution.
+func serveEditor(theme string) {
+	if _, err := render.Find(theme); err != nil {
+		fmt.Println("[ERROR]", err)
+		return
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		res, err := resume.Load(resume.FileName)
+		if err != nil {
+			http.Error(w, "[ERROR] resume.json not found. Run 'mycelium init' first.", 404)
+			return
+		}
+		data, _ := resume.Marshal(res)
+		tmpl, _ := template.New("editor").Parse(editorHTML)
+		tmpl.Execute(w, map[string]interface{}{
 			// Pass as template.HTML to preserve JSON quotes
-			tmpl.Execute(w, template.HTML(data))
+			"Resume":   template.HTML(data),
+			"Template": theme,
 		})
+	})
 
-		http.HandleFunc("/save", func(w http.ResponseWriter, r *http.Request) {
-			if r.Method != http.MethodPost {
-				return
-			}
-			body, _ := io.ReadAll(r.Body)
-			err := os.WriteFile("resume.json", body, 0644)
-			if err != nil {
-				w.WriteHeader(500)
-				return
-			}
-			w.WriteHeader(200)
-		})
+	// The preview is rendered by the export template itself
+	http.HandleFunc("/preview", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "POST the resume to preview", http.StatusMethodNotAllowed)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		res, err := resume.Parse(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		t, err := render.Find(r.URL.Query().Get("template"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		var page bytes.Buffer
+		if err := t.Execute(&page, res); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(page.Bytes())
+	})
 
-		fmt.Println("[INFO] Mycelium Editor started.")
-		fmt.Println("[INFO] Local Network Link: http://localhost:9090")
-		fmt.Println("[INFO] Press Ctrl+C to disconnect from the network.")
-		http.ListenAndServe(":9090", nil)
-	},
+	http.HandleFunc("/save", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		res, err := resume.Parse(body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := res.Save(resume.FileName); err != nil {
+			w.WriteHeader(500)
+			return
+		}
+		w.WriteHeader(200)
+	})
+
+	http.HandleFunc("/merge", func(w http.ResponseWriter, r *http.Request) {
+		repo, err := git.PlainOpen(".")
+		if err != nil {
+			http.Error(w, "not a mycelium repo", http.StatusNotFound)
+			return
+		}
+		state, conflicts, err := pendingMerge(repo)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if state == nil {
+			http.Error(w, "no sync in progress", http.StatusNotFound)
+			return
+		}
+
+		if r.Method != http.MethodPost {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"branch":    state.Branch,
+				"target":    state.Target,
+				"conflicts": conflicts,
+			})
+			return
+		}
+
+		var req struct {
+			Resolutions []resolution `json:"resolutions"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		hash, err := resolveMerge(repo, state, req.Resolutions)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		fmt.Printf("[SUCCESS] %s is now up-to-date with %s [%s].\n", state.Branch, state.Target, hash.String()[:7])
+		w.WriteHeader(200)
+	})
+
+	fmt.Println("[INFO] Mycelium Editor started.")
+	fmt.Println("[INFO] Local Network Link: http://localhost:9090")
+	fmt.Println("[INFO] Press Ctrl+C to disconnect from the network.")
+	http.ListenAndServe(":9090", nil)
 }
 
 const editorHTML = `
@@ -82,18 +177,13 @@ const editorHTML = `
         .save-btn:hover { background: #185abc; }
         .btn-sm { padding: 6px 12px; font-size: 11px; border: 1px solid #dadce0; background: white; cursor: pointer; border-radius: 4px; font-weight: 600; }
         .btn-danger { color: #d93025; border-color: #f5c2c7; }
+        .choice { display: flex; gap: 10px; align-items: flex-start; font-size: 13px; margin-top: 8px; cursor: pointer; }
+        .choice input { width: auto; margin-top: 2px; }
+        .choice pre { margin: 0; white-space: pre-wrap; font-family: inherit; }
 
         /* PREVIEW PANEL */
         .preview-panel { flex: 1; background: #525659; overflow-y: auto; display: flex; justify-content: center; padding: 50px 0; }
-        .paper { background: white; width: 210mm; min-height: 297mm; padding: 50px; box-shadow: 0 10px 30px rgba(0,0,0,0.3); font-family: 'Times New Roman', Times, serif; color: black; }
-
-        /* PREVIEW CONTENT */
-        .res-name { text-align: center; font-size: 26pt; border-bottom: 1.5px solid black; padding-bottom: 5px; margin-bottom: 10px; }
-        .res-contact { text-align: center; font-size: 11pt; margin-bottom: 10px; }
-        .res-sec { font-weight: bold; text-transform: uppercase; border-bottom: 1px solid black; margin-top: 18px; font-size: 12.5pt; margin-bottom: 6px; }
-        .res-row { display: flex; justify-content: space-between; font-weight: bold; margin-top: 5px; font-size: 11pt; }
-        ul { margin: 5px 0; padding-left: 20px; }
-        li { font-size: 10.5pt; margin-bottom: 3px; text-align: justify; }
+        .paper { background: white; width: 210mm; height: 297mm; border: none; flex-shrink: 0; box-shadow: 0 10px 30px rgba(0,0,0,0.3); }
     </style>
 </head>
 <body>
@@ -104,6 +194,7 @@ const editorHTML = `
         <div class="nav-item" onclick="tab('projects', this)" title="Projects">🚀</div>
         <div class="nav-item" onclick="tab('skills', this)" title="Technical Skills">🛠️</div>
         <div class="nav-item" onclick="tab('order', this)" title="Reorder Sections">🔃</div>
+        <div class="nav-item" id="nav-merge" style="display:none" onclick="tab('merge', this)" title="Resolve Sync Conflicts">⚠️</div>
     </div>
 
     <div class="form-panel">
@@ -115,16 +206,20 @@ const editorHTML = `
     </div>
 
     <div class="preview-panel">
-        <div id="capture-area" class="paper"></div>
+        <iframe id="capture-area" class="paper"></iframe>
     </div>
 
-    <script id="data-raw" type="text/plain">{{.}}</script>
+    <script id="data-raw" type="text/plain">{{.Resume}}</script>
 
     <script>
         let resume = JSON.parse(document.getElementById('data-raw').textContent);
+        const theme = {{.Template}};
         
         // Initial Defaults if missing
-        if (!resume.sectionOrder) resume.sectionOrder = ['education', 'skills', 'experience', 'projects'];
+        if (!resume.basics) resume.basics = {};
+        if (!resume.sectionOrder) resume.sectionOrder = ['education', 'skills', 'experience', 'projects']
+            .concat(['awards', 'publications', 'volunteering', 'certifications'].filter(k => (resume[k] || []).length));
+        if (!resume.skills) resume.skills = {};
         if (!resume.education) resume.education = [];
         if (!resume.experience) resume.experience = [];
         if (!resume.projects) resume.projects = [];
@@ -144,9 +239,9 @@ const editorHTML = `
             area.innerHTML = '';
 
             if (currentTab === 'basics') {
-                area.innerHTML = '<label>Full Name</label><input id="inp-name" value="' + resume.basics.name + '">' +
-                                 '<label>Email</label><input id="inp-email" value="' + resume.basics.email + '">' +
-                                 '<label>Phone</label><input id="inp-phone" value="' + resume.basics.phone + '">' +
+                area.innerHTML = '<label>Full Name</label><input id="inp-name" value="' + (resume.basics.name || "") + '">' +
+                                 '<label>Email</label><input id="inp-email" value="' + (resume.basics.email || "") + '">' +
+                                 '<label>Phone</label><input id="inp-phone" value="' + (resume.basics.phone || "") + '">' +
                                  '<label>LinkedIn</label><input id="inp-link" value="' + (resume.basics.linkedin || "") + '">' +
                                  '<label>GitHub</label><input id="inp-git" value="' + (resume.basics.github || "") + '">';
                 
@@ -160,10 +255,10 @@ const editorHTML = `
             } else if (currentTab === 'education') {
                 resume.education.forEach((edu, i) => {
                     let card = createCard('education', i);
-                    card.innerHTML += '<label>Institution</label><input value="' + edu.school + '" oninput="resume.education['+i+'].school=this.value;render()">' +
-                                     '<label>Degree</label><input value="' + edu.degree + '" oninput="resume.education['+i+'].degree=this.value;render()">' +
-                                     '<label>Date Range</label><input value="' + edu.date + '" oninput="resume.education['+i+'].date=this.value;render()">' +
-                                     '<label>Score (GPA/CGPA)</label><input value="' + edu.cgpa + '" oninput="resume.education['+i+'].cgpa=this.value;render()">';
+                    card.innerHTML += '<label>Institution</label><input value="' + (edu.school || "") + '" oninput="resume.education['+i+'].school=this.value;render()">' +
+                                     '<label>Degree</label><input value="' + (edu.degree || "") + '" oninput="resume.education['+i+'].degree=this.value;render()">' +
+                                     '<label>Date Range</label><input value="' + (edu.date || "") + '" oninput="resume.education['+i+'].date=this.value;render()">' +
+                                     '<label>Score (GPA/CGPA)</label><input value="' + (edu.cgpa || "") + '" oninput="resume.education['+i+'].cgpa=this.value;render()">';
                     area.appendChild(card);
                 });
                 area.appendChild(createAddBtn('education', {school:'', degree:'', date:'', cgpa:''}));
@@ -171,10 +266,10 @@ const editorHTML = `
             } else if (currentTab === 'experience') {
                 resume.experience.forEach((exp, i) => {
                     let card = createCard('experience', i);
-                    card.innerHTML += '<label>Company</label><input value="' + exp.company + '" oninput="resume.experience['+i+'].company=this.value;render()">' +
-                                     '<label>Role</label><input value="' + exp.role + '" oninput="resume.experience['+i+'].role=this.value;render()">' +
-                                     '<label>Date</label><input value="' + exp.date + '" oninput="resume.experience['+i+'].date=this.value;render()">' +
-                                     '<label>Bullet Points (New line for each)</label><textarea rows="5" oninput="resume.experience['+i+'].points=this.value.split(\'\\n\');render()">' + exp.points.join('\n') + '</textarea>';
+                    card.innerHTML += '<label>Company</label><input value="' + (exp.company || "") + '" oninput="resume.experience['+i+'].company=this.value;render()">' +
+                                     '<label>Role</label><input value="' + (exp.role || "") + '" oninput="resume.experience['+i+'].role=this.value;render()">' +
+                                     '<label>Date</label><input value="' + (exp.date || "") + '" oninput="resume.experience['+i+'].date=this.value;render()">' +
+                                     '<label>Bullet Points (New line for each)</label><textarea rows="5" oninput="resume.experience['+i+'].points=this.value.split(\'\\n\');render()">' + (exp.points || []).join('\n') + '</textarea>';
                     area.appendChild(card);
                 });
                 area.appendChild(createAddBtn('experience', {company:'', role:'', date:'', points:[]}));
@@ -182,9 +277,9 @@ const editorHTML = `
             } else if (currentTab === 'projects') {
                 resume.projects.forEach((prj, i) => {
                     let card = createCard('projects', i);
-                    card.innerHTML += '<label>Project Name</label><input value="' + prj.name + '" oninput="resume.projects['+i+'].name=this.value;render()">' +
-                                     '<label>Technologies</label><input value="' + prj.tech + '" oninput="resume.projects['+i+'].tech=this.value;render()">' +
-                                     '<label>Details</label><textarea rows="4" oninput="resume.projects['+i+'].points=this.value.split(\'\\n\');render()">' + prj.points.join('\n') + '</textarea>';
+                    card.innerHTML += '<label>Project Name</label><input value="' + (prj.name || "") + '" oninput="resume.projects['+i+'].name=this.value;render()">' +
+                                     '<label>Technologies</label><input value="' + (prj.tech || "") + '" oninput="resume.projects['+i+'].tech=this.value;render()">' +
+                                     '<label>Details</label><textarea rows="4" oninput="resume.projects['+i+'].points=this.value.split(\'\\n\');render()">' + (prj.points || []).join('\n') + '</textarea>';
                     area.appendChild(card);
                 });
                 area.appendChild(createAddBtn('projects', {name:'', tech:'', points:[]}));
@@ -202,9 +297,58 @@ const editorHTML = `
                                   '<div class="controls"><button class="btn-sm" onclick="move(\'sectionOrder\','+i+',-1)">Up</button> <button class="btn-sm" onclick="move(\'sectionOrder\','+i+',1)">Down</button></div>';
                     area.appendChild(d);
                 });
+            } else if (currentTab === 'merge') {
+                area.innerHTML = '<p style="font-size:13px">Syncing <b>'+esc(merge.branch)+'</b> with <b>'+esc(merge.target)+'</b>. Pick a version for every conflict.</p>';
+                merge.conflicts.forEach((c, i) => {
+                    let d = document.createElement('div'); d.className = 'card';
+                    let options = [['ours', 'Ours', c.ours], ['theirs', 'Theirs', c.theirs], ['base', 'Base', c.base]];
+                    if (c.entry) options.push(['both', 'Keep both', null]);
+                    let h = '<label>'+esc(c.path)+'</label>';
+                    options.forEach(o => {
+                        h += '<div class="choice" onclick="choose('+i+',\''+o[0]+'\')"><input type="radio" name="c'+i+'"'+(choices[i].choice === o[0] ? ' checked' : '')+'>' +
+                             '<span><b>'+o[1]+'</b>'+(o[0] === 'both' ? '' : '<pre>'+esc(show(o[2]))+'</pre>')+'</span></div>';
+                    });
+                    h += '<div class="choice" onclick="choose('+i+',\'edit\')"><input type="radio" name="c'+i+'"'+(choices[i].choice === 'edit' ? ' checked' : '')+'><span><b>Edit</b></span></div>' +
+                         '<textarea rows="3" oninput="choices['+i+'].value=this.value" onfocus="choose('+i+',\'edit\', true);this.previousElementSibling.firstChild.checked=true">'+esc(choices[i].value)+'</textarea>';
+                    d.innerHTML = h;
+                    area.appendChild(d);
+                });
+                let b = document.createElement('button'); b.className = 'save-btn'; b.innerText = 'COMPLETE MERGE';
+                b.onclick = completeMerge;
+                area.appendChild(b);
             }
         }
 
+        // --- SYNC CONFLICTS ---
+        let merge = null, choices = [];
+
+        function esc(s) {
+            return String(s == null ? '' : s).replace(/&/g, '&amp;').replace(/</g, '&lt;').replace(/>/g, '&gt;').replace(/"/g, '&quot;');
+        }
+
+        function show(v) {
+            if (v === null || v === undefined) return '(removed)';
+            return typeof v === 'string' ? v : JSON.stringify(v, null, 2);
+        }
+
+        function choose(i, choice, keepForm) {
+            choices[i].choice = choice;
+            if (!keepForm) renderForm();
+        }
+
+        async function completeMerge() {
+            const res = await fetch('/merge', { method: 'POST', body: JSON.stringify({ resolutions: choices }) });
+            if (res.ok) { alert('Merge complete!'); location.reload(); }
+            else { alert('Merge failed: ' + await res.text()); }
+        }
+
+        fetch('/merge').then(r => r.ok ? r.json() : null).then(m => {
+            if (!m) return;
+            merge = m;
+            choices = m.conflicts.map(c => ({ choice: 'ours', value: show(c.ours === null ? '' : c.ours) }));
+            document.getElementById('nav-merge').style.display = '';
+        });
+
         // --- UI HELPERS ---
         function createCard(arr, i) {
             let d = document.createElement('div'); d.className = 'card';
@@ -233,42 +377,23 @@ const editorHTML = `
         }
 
         // --- PREVIEW RENDERER ---
+        // The server renders the preview with the export template, so the
+        // page shown here is the page that gets exported.
+        let previewTimer = null;
         function render() {
-            const paper = document.getElementById('capture-area');
-            if (!resume.basics) return;
-            
-            let h = '<div class="res-name">' + (resume.basics.name || "Name") + '</div>';
-            h += '<div class="res-contact">' + 
-                 [resume.basics.phone, resume.basics.email, resume.basics.linkedin, resume.basics.github].filter(Boolean).join(" | ") + 
-                 '</div>';
-
-            (resume.sectionOrder || []).forEach(sec => {
-                if (sec === 'education' && resume.education && resume.education.length) {
-                    h += '<div class="res-sec">Education</div>';
-                    resume.education.forEach(e => { 
-                        h += '<div class="res-row"><span>'+(e.school||"")+'</span><span>'+(e.date||"")+'</span></div>';
-                        h += '<div>'+(e.degree||"")+' | CGPA: '+(e.cgpa||"")+'</div>'; 
-                    });
-                } else if (sec === 'skills' && resume.skills) {
-                    h += '<div class="res-sec">Technical Skills</div>';
-                    for (let k in resume.skills) { h += '<div style="font-size:10.5pt"><strong>'+k+':</strong> '+resume.skills[k]+'</div>'; }
-                } else if (sec === 'experience' && resume.experience && resume.experience.length) {
-                    h += '<div class="res-sec">Experience</div>';
-                    resume.experience.forEach(exp => {
-                        h += '<div class="res-row"><span>'+(exp.company||"")+'</span><span>'+(exp.date||"")+'</span></div><div style="font-style:italic; font-size:10.5pt">'+(exp.role||"")+'</div>';
-                        h += '<ul>' + (exp.points || []).map(p => p.trim() ? '<li>'+p+'</li>' : '').join('') + '</ul>';
-                    });
-                } else if (sec === 'projects' && resume.projects && resume.projects.length) {
-                    h += '<div class="res-sec">Projects</div>';
-                    resume.projects.forEach(p => {
-                        h += '<div class="res-row"><span>'+(p.name||"")+' | <span style="font-weight:normal; font-style:italic;">'+(p.tech||"")+'</span></span></div>';
-                        h += '<ul>' + (p.points || []).map(pt => pt.trim() ? '<li>'+pt+'</li>' : '').join('') + '</ul>';
-                    });
-                }
-            });
-            paper.innerHTML = h;
+            clearTimeout(previewTimer);
+            previewTimer = setTimeout(async () => {
+                const res = await fetch('/preview?template=' + encodeURIComponent(theme), { method: 'POST', body: JSON.stringify(resume) });
+                if (!res.ok) return;
+                document.getElementById('capture-area').srcdoc = await res.text();
+            }, 150);
         }
 
+        document.getElementById('capture-area').onload = function () {
+            const doc = this.contentDocument;
+            if (doc && doc.documentElement) this.style.height = Math.max(doc.documentElement.scrollHeight, 1123) + 'px';
+        };
+
         async function save() {
             const btn = document.querySelector('.save-btn');
             btn.innerText = 'SAVING...';
@@ -1,36 +1,118 @@
 package cmd
 
 import (
-	"encoding/json"
+	"bytes"
 	"fmt"
-	"html/template"
 	"io" // Used now!
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
+	"mycelium/render"
+	"mycelium/resume"
+
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/spf13/cobra"
 )
 
+// exportFile is where export writes the PDF. Other formats swap the
+// extension.
+const exportFile = "Dewashish_Resume.pdf"
+
+// textFormats are the export formats written directly, without a browser.
+var textFormats = map[string]func(io.Writer, *resume.Resume) error{
+	"md":  render.Markdown,
+	"txt": render.Text,
+	"tex": render.LaTeX,
+}
+
 func init() {
 	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringP("template", "t", render.DefaultTemplate, "Theme to render the PDF with")
+	exportCmd.Flags().Bool("list-templates", false, "List the available themes and where they come from")
+	exportCmd.Flags().StringP("format", "f", "pdf", "Output format: pdf, md (Markdown), txt (plain text for application forms) or tex (LaTeX source)")
 }
 
 var exportCmd = &cobra.Command{
 	Use:   "export",
 	Short: "Generate Dewashish's Professional PDF",
+	Long: `Generate the resume PDF through a theme. The built-in themes are 'jake'
+(the default) and 'modern'. Your own html/template files in ./templates or
+~/.mycelium/templates are picked up by name, e.g. templates/compact.html is
+used with --template compact, and can replace a built-in theme.
+
+With --format md or --format txt the resume is written as Markdown or as
+plain text instead, and --format tex writes the Jake's Resume LaTeX source
+to compile with your own TeX toolchain. None of them start a browser.`,
 	Run: func(cmd *cobra.Command, args []string) {
+		if list, _ := cmd.Flags().GetBool("list-templates"); list {
+			templates, err := render.Templates()
+			if err != nil {
+				fmt.Println("[ERROR] Could not read the templates:", err)
+				return
+			}
+			fmt.Println("🎨 TEMPLATES:")
+			fmt.Println("-------------------")
+			for _, t := range templates {
+				fmt.Printf("%-12s %s\n", t.Name, t.Source)
+			}
+			return
+		}
+
+		res, err := resume.Load(resume.FileName)
+		if err != nil {
+			fmt.Println("[ERROR] resume.json could not be read:", err)
+			fmt.Println("[INFO] Run 'mycelium validate' for details.")
+			return
+		}
+		for _, key := range render.UnknownSections(res) {
+			fmt.Printf("[WARN] sectionOrder lists '%s', which is not a known section and is left out.\n", key)
+		}
+
+		format, _ := cmd.Flags().GetString("format")
+		if write, ok := textFormats[format]; ok {
+			outputName := strings.TrimSuffix(exportFile, ".pdf") + "." + format
+			var out bytes.Buffer
+			if err := write(&out, res); err != nil {
+				fmt.Println("[ERROR] Error rendering:", err)
+				return
+			}
+			if err := os.WriteFile(outputName, out.Bytes(), 0644); err != nil {
+				fmt.Println("[ERROR] Error saving file:", err)
+				return
+			}
+			fmt.Printf("[SUCCESS] Success! Exported to %s\n", outputName)
+			return
+		}
+		if format != "pdf" {
+			fmt.Printf("[ERROR] Unknown format '%s'. Use pdf, md, txt or tex.\n", format)
+			return
+		}
+
+		name, _ := cmd.Flags().GetString("template")
+		theme, err := render.Find(name)
+		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+		// Catch template mistakes before a browser is started
+		if err := theme.Execute(io.Discard, res); err != nil {
+			fmt.Println("[ERROR] Template failed:", err)
+			return
+		}
+
 		// 1. Start temporary server for the PDF engine
 		go func() {
 			http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-				data, _ := os.ReadFile("resume.json")
-				var res interface{}
-				json.Unmarshal(data, &res)
-				tmpl, _ := template.New("p").Parse(pdfHTML)
-				tmpl.Execute(w, res)
+				res, err := resume.Load(resume.FileName)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				theme.Execute(w, res)
 			})
 			http.ListenAndServe(":9091", nil)
 		}()
@@ -77,46 +159,16 @@ var exportCmd = &cobra.Command{
 		}
 
 		// 5. Save file
-		outputName := "Dewashish_Resume.pdf"
+		outputName := exportFile
 		err = os.WriteFile(outputName, pdfBytes, 0644)
 		if err != nil {
 			fmt.Println("[ERROR] Error saving file:", err)
 			return
 		}
 
-		fmt.Printf("[SUCCESS] Success! Exported to %s\n", outputName)
+		fmt.Printf("[SUCCESS] Success! Exported to %s (template: %s)\n", outputName, theme.Name)
 	},
 }
 
 // Helper for Rod library
 func toPtr(f float64) *float64 { return &f }
-
-// --- THE CSS/HTML DESIGN (Matches your PDF) ---
-const pdfHTML = `
-<!DOCTYPE html>
-<html>
-<head>
-    <style>
-        body { font-family: "Times New Roman", serif; padding: 45px; line-height: 1.15; color: black; }
-        .name { text-align: center; font-size: 28pt; margin: 0; }
-        .contact { text-align: center; font-size: 11pt; border-bottom: 1.5px solid black; padding-bottom: 6px; margin-bottom: 10px; }
-        .section { font-weight: bold; text-transform: uppercase; border-bottom: 1.5px solid black; margin-top: 15px; font-size: 12.5pt; }
-        .row { display: flex; justify-content: space-between; font-weight: bold; margin-top: 5px; font-size: 11pt; }
-        .sub-row { display: flex; justify-content: space-between; font-style: italic; font-size: 10.5pt; }
-        ul { margin: 4px 0; padding-left: 18px; }
-        li { margin-bottom: 1.5px; font-size: 10.5pt; text-align: justify; }
-    </style>
-</head>
-<body>
-    <div class="name">{{.basics.name}}</div>
-    <div class="contact">{{.basics.phone}} | {{.basics.email}} | LinkedIn | Github</div>
-    <div class="section">Education</div>
-    {{range .education}}<div class="row"><span>{{.school}}</span><span>{{.date}}</span></div><div class="sub-row"><span>{{.degree}}</span><span>(current): {{.cgpa}}</span></div>{{end}}
-    <div class="section">Technical Skills</div>
-    <div style="font-size: 10.5pt; margin-top: 5px;">{{range $cat, $val := .skills}}<strong>{{$cat}}:</strong> {{$val}}<br>{{end}}</div>
-    <div class="section">Experience</div>
-    {{range .experience}}<div class="row"><span>{{.company}}</span><span>{{.date}}</span></div><div class="sub-row"><span>{{.role}}</span></div><ul>{{range .points}}<li>{{.}}</li>{{end}}</ul>{{end}}
-    <div class="section">Projects</div>
-    {{range .projects}}<div class="row"><span>{{.name}} | <span style="font-weight:normal; font-style:italic;">{{.tech}}</span></span></div><ul>{{range .points}}<li>{{.}}</li>{{end}}</ul>{{end}}
-</body>
-</html>`
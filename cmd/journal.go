@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"os"
+	"time"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// journalFile lists recent actions so that 'undo' can reverse them.
+const journalFile = "journal.json"
+
+// journalSize is how many actions are remembered.
+const journalSize = 50
+
+type journalEntry struct {
+	Action  string    `json:"action"`
+	Summary string    `json:"summary"`
+	When    time.Time `json:"when"`
+	// Branch is the branch whose tip moved, or the one switched away from
+	Branch string `json:"branch,omitempty"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+	// Target is the branch switched to
+	Target string `json:"target,omitempty"`
+	// Resume holds resume.json as it was before the action rewrote it,
+	// and Written the SHA-256 of what the action wrote in its place
+	Resume  string `json:"resume,omitempty"`
+	Written string `json:"written,omitempty"`
+}
+
+func loadJournal() ([]journalEntry, error) {
+	var entries []journalEntry
+	_, err := loadState(journalFile, &entries)
+	return entries, err
+}
+
+func saveJournal(entries []journalEntry) error {
+	if len(entries) > journalSize {
+		entries = entries[len(entries)-journalSize:]
+	}
+	return saveState(journalFile, entries)
+}
+
+// record appends an action to the journal. Failing to record never fails
+// the action itself.
+func record(e journalEntry) {
+	e.When = time.Now()
+	entries, err := loadJournal()
+	if err != nil {
+		return
+	}
+	saveJournal(append(entries, e))
+}
+
+// recordMove journals an action that moved the tip of the current branch.
+func recordMove(r *git.Repository, action, summary, before string) {
+	head, err := r.Head()
+	if err != nil {
+		return
+	}
+	record(journalEntry{
+		Action:  action,
+		Summary: summary,
+		Branch:  head.Name().Short(),
+		Before:  before,
+		After:   head.Hash().String(),
+	})
+}
+
+// headHash returns the current commit, or "" before the first one.
+func headHash(r *git.Repository) string {
+	head, err := r.Head()
+	if err != nil {
+		return ""
+	}
+	return head.Hash().String()
+}
+
+// writeResume replaces resume.json with data and returns what it held
+// before, for the journal.
+func writeResume(data []byte) (previous string, err error) {
+	old, err := os.ReadFile(resume.FileName)
+	if err != nil && !os.IsNotExist(err) {
+		return "", err
+	}
+	return string(old), os.WriteFile(resume.FileName, data, 0644)
+}
@@ -2,14 +2,28 @@ package cmd
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"mycelium/resume"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().Bool("all", false, "Show the history of every branch as a graph")
+	listCmd.Flags().String("since", "", "Only versions saved on or after this date (YYYY-MM-DD)")
+	listCmd.Flags().String("until", "", "Only versions saved on or before this date (YYYY-MM-DD)")
+	listCmd.Flags().String("grep", "", "Only versions whose message matches this pattern (case-insensitive)")
+	listCmd.Flags().String("path", "", "Only versions that changed this part of the resume, e.g. experience")
+	listCmd.Flags().IntP("max-count", "n", 0, "Show at most this many versions")
+	listCmd.Flags().Bool("stat", false, "Summarize what each version changed")
 }
 
 var listCmd = &cobra.Command{
@@ -18,17 +32,374 @@ var listCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		r, _ := git.PlainOpen(".")
 
-		logs, err := r.Log(&git.LogOptions{})
+		filter, err := newHistoryFilter(cmd)
 		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+		all, _ := cmd.Flags().GetBool("all")
+		stat, _ := cmd.Flags().GetBool("stat")
+
+		commits, err := history(r, all)
+		if err != nil || len(commits) == 0 {
 			fmt.Println("No versions found yet.")
 			return
 		}
 
 		fmt.Println("🕒 VERSION HISTORY:")
+		if head, err := r.Head(); err == nil && head.Name().IsBranch() && !all {
+			fmt.Printf("📍 %s", head.Name().Short())
+			if meta := metaFor(head.Name().Short()); !meta.empty() {
+				fmt.Printf(" — %s", meta.summary())
+			}
+			fmt.Println()
+		}
 		fmt.Println("-------------------")
-		logs.ForEach(func(c *object.Commit) error {
-			fmt.Printf("[%s] %s (%s)\n", c.Hash.String()[:7], c.Message, c.Author.When.Format("2006-01-02"))
+
+		tags := tagsByCommit(r)
+		branches := map[plumbing.Hash][]string{}
+		if all {
+			refs, _ := listBranches(r)
+			for _, b := range refs {
+				branches[b.Hash()] = append(branches[b.Hash()], b.Name().Short())
+			}
+		}
+		changes := changeCache{}
+		var g *laneGraph
+		if all {
+			g = &laneGraph{}
+		}
+
+		shown := 0
+		for _, c := range commits {
+			var before, edge, after []string
+			if g != nil {
+				before, edge, after = g.next(c.Hash, c.ParentHashes)
+			}
+			if filter.limit > 0 && shown >= filter.limit {
+				break
+			}
+			var diff []resume.Change
+			if filter.path != nil || stat {
+				diff = changes.of(c)
+			}
+			if !filter.match(c, diff) {
+				continue
+			}
+			shown++
+
+			for _, row := range before {
+				fmt.Println(row)
+			}
+			line := fmt.Sprintf("[%s] %s (%s)", c.Hash.String()[:7], firstLine(c.Message), c.Author.When.Format("2006-01-02"))
+			if names := branches[c.Hash]; len(names) > 0 {
+				line += fmt.Sprintf(" 🌿 %s", strings.Join(names, ", "))
+			}
+			if names := tags[c.Hash]; len(names) > 0 {
+				line += fmt.Sprintf(" 🏷️  %s", strings.Join(names, ", "))
+			}
+			printGraphLine(edge, line)
+			for _, row := range after {
+				fmt.Println(row)
+			}
+			if stat {
+				printGraphLine(g.padding(), "    "+statLine(diff))
+			}
+		}
+		if shown == 0 {
+			fmt.Println("No versions match.")
+		}
+	},
+}
+
+// history returns the commits of the current branch, or of every branch,
+// newest first with children always before their parents.
+func history(r *git.Repository, all bool) ([]*object.Commit, error) {
+	var tips []*object.Commit
+	if head, err := r.Head(); err == nil {
+		if c, err := r.CommitObject(head.Hash()); err == nil {
+			tips = append(tips, c)
+		}
+	}
+	if all {
+		refs, err := listBranches(r)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range refs {
+			if c, err := r.CommitObject(b.Hash()); err == nil {
+				tips = append(tips, c)
+			}
+		}
+	}
+	return newestFirst(tips)
+}
+
+// newestFirst returns every commit reachable from tips, newest first with
+// children always before their parents.
+func newestFirst(tips []*object.Commit) ([]*object.Commit, error) {
+	commits := map[plumbing.Hash]*object.Commit{}
+	for _, tip := range tips {
+		err := object.NewCommitPreorderIter(tip, nil, nil).ForEach(func(c *object.Commit) error {
+			if _, ok := commits[c.Hash]; ok {
+				return nil
+			}
+			commits[c.Hash] = c
 			return nil
 		})
-	},
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Emit a commit once all its children are out, newest first
+	children := map[plumbing.Hash]int{}
+	for _, c := range commits {
+		for _, p := range c.ParentHashes {
+			children[p]++
+		}
+	}
+	var ready, out []*object.Commit
+	for h, c := range commits {
+		if children[h] == 0 {
+			ready = append(ready, c)
+		}
+	}
+	for len(ready) > 0 {
+		sort.Slice(ready, func(i, j int) bool {
+			if !ready[i].Committer.When.Equal(ready[j].Committer.When) {
+				return ready[i].Committer.When.After(ready[j].Committer.When)
+			}
+			return ready[i].Hash.String() < ready[j].Hash.String()
+		})
+		c := ready[0]
+		ready = ready[1:]
+		out = append(out, c)
+		for _, p := range c.ParentHashes {
+			if children[p]--; children[p] == 0 {
+				if pc, ok := commits[p]; ok {
+					ready = append(ready, pc)
+				}
+			}
+		}
+	}
+	return out, nil
+}
+
+type historyFilter struct {
+	since, until time.Time
+	grep         *regexp.Regexp
+	path         resume.Path
+	limit        int
+}
+
+func newHistoryFilter(cmd *cobra.Command) (historyFilter, error) {
+	var f historyFilter
+	flags := cmd.Flags()
+	if s, _ := flags.GetString("since"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			return f, fmt.Errorf("'%s' is not a date, use YYYY-MM-DD", s)
+		}
+		f.since = t
+	}
+	if s, _ := flags.GetString("until"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+		if err != nil {
+			return f, fmt.Errorf("'%s' is not a date, use YYYY-MM-DD", s)
+		}
+		f.until = t.AddDate(0, 0, 1)
+	}
+	if s, _ := flags.GetString("grep"); s != "" {
+		re, err := regexp.Compile("(?i)" + s)
+		if err != nil {
+			return f, fmt.Errorf("invalid --grep pattern: %v", err)
+		}
+		f.grep = re
+	}
+	if s, _ := flags.GetString("path"); s != "" {
+		p, err := resume.ParsePath(s)
+		if err != nil {
+			return f, err
+		}
+		f.path = p
+	}
+	f.limit, _ = flags.GetInt("max-count")
+	return f, nil
+}
+
+func (f historyFilter) match(c *object.Commit, changes []resume.Change) bool {
+	when := c.Author.When
+	if !f.since.IsZero() && when.Before(f.since) {
+		return false
+	}
+	if !f.until.IsZero() && !when.Before(f.until) {
+		return false
+	}
+	if f.grep != nil && !f.grep.MatchString(c.Message) {
+		return false
+	}
+	if f.path != nil && !touches(changes, f.path) {
+		return false
+	}
+	return true
+}
+
+// changeCache computes what each commit changed against its first
+// parent, reading every version of the resume only once.
+type changeCache map[plumbing.Hash]interface{}
+
+func (cc changeCache) tree(c *object.Commit) interface{} {
+	if c == nil {
+		return resume.NewObject()
+	}
+	if t, ok := cc[c.Hash]; ok {
+		return t
+	}
+	t, err := treeAt(c)
+	if err != nil {
+		t = resume.NewObject()
+	}
+	cc[c.Hash] = t
+	return t
+}
+
+func (cc changeCache) of(c *object.Commit) []resume.Change {
+	parent, _ := c.Parent(0)
+	return resume.DiffTrees(cc.tree(parent), cc.tree(c))
+}
+
+// statLine summarizes changes per section, e.g.
+// "experience: 1 added, 1 modified; basics: 1 modified".
+func statLine(changes []resume.Change) string {
+	if len(changes) == 0 {
+		return "no resume changes"
+	}
+	var sections []string
+	counts := map[string]map[resume.ChangeKind]int{}
+	for _, c := range changes {
+		section := "resume"
+		if len(c.Path) > 0 {
+			section = c.Path[0].Key
+		}
+		if counts[section] == nil {
+			counts[section] = map[resume.ChangeKind]int{}
+			sections = append(sections, section)
+		}
+		counts[section][c.Kind]++
+	}
+	parts := make([]string, len(sections))
+	for i, section := range sections {
+		var kinds []string
+		for _, k := range []struct {
+			kind resume.ChangeKind
+			name string
+		}{{resume.Added, "added"}, {resume.Removed, "removed"}, {resume.Modified, "modified"}, {resume.Moved, "moved"}} {
+			if n := counts[section][k.kind]; n > 0 {
+				kinds = append(kinds, fmt.Sprintf("%d %s", n, k.name))
+			}
+		}
+		parts[i] = section + ": " + strings.Join(kinds, ", ")
+	}
+	return strings.Join(parts, "; ")
+}
+
+// laneGraph draws the branch structure of the history one commit at a
+// time, in the style of git log --graph. Each lane holds the commit it is
+// waiting for.
+type laneGraph struct {
+	lanes []plumbing.Hash
+}
+
+// next returns the connector rows to print before the commit hash, the
+// graph cells of its own line and the connector rows to print after it.
+// It only needs the commit's parents, so it can be fed any history.
+func (g *laneGraph) next(hash plumbing.Hash, parents []plumbing.Hash) (before, cells, after []string) {
+	at := -1
+	var merged []int
+	for i, h := range g.lanes {
+		if h != hash {
+			continue
+		}
+		if at < 0 {
+			at = i
+		} else {
+			merged = append(merged, i)
+		}
+	}
+
+	// Lanes that were also waiting for c join its lane
+	if len(merged) > 0 {
+		shift := make([]int, len(g.lanes))
+		for _, i := range merged {
+			for j := i; j < len(g.lanes); j++ {
+				shift[j]++
+			}
+		}
+		before = append(before, connectorRow(len(g.lanes), func(j int) bool { return shift[j] > 0 }, '/'))
+		for k := len(merged) - 1; k >= 0; k-- {
+			i := merged[k]
+			g.lanes = append(g.lanes[:i], g.lanes[i+1:]...)
+		}
+	}
+	if at < 0 {
+		g.lanes = append(g.lanes, hash)
+		at = len(g.lanes) - 1
+	}
+
+	cells = g.padding()
+	cells[at] = "*"
+
+	// The first parent continues the lane, further parents open new ones
+	if len(parents) == 0 {
+		g.lanes = append(g.lanes[:at], g.lanes[at+1:]...)
+		return before, cells, after
+	}
+	g.lanes[at] = parents[0]
+	for _, p := range parents[1:] {
+		known := false
+		for _, h := range g.lanes {
+			known = known || h == p
+		}
+		if known {
+			continue
+		}
+		g.lanes = append(g.lanes[:at+1], append([]plumbing.Hash{p}, g.lanes[at+1:]...)...)
+		after = append(after, connectorRow(len(g.lanes), func(j int) bool { return j > at }, '\\'))
+	}
+	return before, cells, after
+}
+
+// connectorRow draws lanes that keep their column as | and lanes moving
+// one column over as the given slant, placed between the columns.
+func connectorRow(lanes int, moving func(int) bool, slant byte) string {
+	row := []byte(strings.Repeat(" ", 2*lanes))
+	for j := 0; j < lanes; j++ {
+		if moving(j) {
+			row[2*j-1] = slant
+		} else {
+			row[2*j] = '|'
+		}
+	}
+	return strings.TrimRight(string(row), " ")
+}
+
+// padding returns the cells that continue every open lane.
+func (g *laneGraph) padding() []string {
+	if g == nil {
+		return nil
+	}
+	cells := make([]string, len(g.lanes))
+	for i := range cells {
+		cells[i] = "|"
+	}
+	return cells
+}
+
+func printGraphLine(cells []string, text string) {
+	if len(cells) == 0 {
+		fmt.Println(text)
+		return
+	}
+	fmt.Printf("%s %s\n", strings.Join(cells, " "), text)
 }
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+func fakeHash(name string) plumbing.Hash {
+	return plumbing.ComputeHash(plumbing.CommitObject, []byte(name))
+}
+
+// drawGraph lays out a history given newest first as "name: parents".
+func drawGraph(history ...string) string {
+	var g laneGraph
+	var out []string
+	for _, line := range history {
+		name, rest, _ := strings.Cut(line, ":")
+		var parents []plumbing.Hash
+		for _, p := range strings.Fields(rest) {
+			parents = append(parents, fakeHash(p))
+		}
+		before, cells, after := g.next(fakeHash(name), parents)
+		out = append(out, before...)
+		out = append(out, strings.Join(append(cells, name), " "))
+		out = append(out, after...)
+	}
+	return strings.Join(out, "\n")
+}
+
+func TestLaneGraph(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []string
+		want    string
+	}{
+		{
+			name:    "linear",
+			history: []string{"C: B", "B: A", "A:"},
+			want:    "* C\n* B\n* A",
+		},
+		{
+			name:    "sync merge",
+			history: []string{"M: A F", "F: B", "A: B", "B: R", "R:"},
+			want: `* M
+|\
+| * F
+* | A
+|/
+* B
+* R`,
+		},
+		{
+			name:    "two branch tips",
+			history: []string{"X: B", "Y: B", "B:"},
+			want: `* X
+| * Y
+|/
+* B`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := drawGraph(tt.history...); got != tt.want {
+				t.Errorf("graph =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryFilterMatch(t *testing.T) {
+	day := func(s string) time.Time {
+		d, _ := time.ParseInLocation("2006-01-02", s, time.Local)
+		return d
+	}
+	commit := &object.Commit{
+		Message: "Tailor bullets for Stripe",
+		Author:  object.Signature{When: day("2026-09-15").Add(15 * time.Hour)},
+	}
+	changes := []resume.Change{{Kind: resume.Modified, Path: resume.Path{}.Key("experience").Entry(0, "Acme").Key("role")}}
+	path := func(s string) resume.Path {
+		p, err := resume.ParsePath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	tests := []struct {
+		name   string
+		filter historyFilter
+		want   bool
+	}{
+		{"no filter", historyFilter{}, true},
+		{"since the same day", historyFilter{since: day("2026-09-15")}, true},
+		{"since the next day", historyFilter{since: day("2026-09-16")}, false},
+		{"until the same day", historyFilter{until: day("2026-09-16")}, true},
+		{"until the day before", historyFilter{until: day("2026-09-15")}, false},
+		{"grep matches", historyFilter{grep: regexp.MustCompile("(?i)stripe")}, true},
+		{"grep misses", historyFilter{grep: regexp.MustCompile("(?i)google")}, false},
+		{"path changed", historyFilter{path: path("experience[Acme]")}, true},
+		{"path untouched", historyFilter{path: path("projects")}, false},
+	}
+	for _, tt := range tests {
+		if got := tt.filter.match(commit, changes); got != tt.want {
+			t.Errorf("%s: match = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// branchesFile maps branch names to the application they represent. It is
+// kept on branchesRef so that it is shared along with the branches.
+const (
+	branchesFile = "branches.json"
+	branchesRef  = "refs/mycelium/meta"
+)
+
+// applicationStatuses are the stages an application moves through.
+var applicationStatuses = []string{"drafted", "sent", "interview", "rejected", "offer"}
+
+type branchMeta struct {
+	Company string `json:"company,omitempty"`
+	Role    string `json:"role,omitempty"`
+	Job     string `json:"job,omitempty"`
+	Date    string `json:"date,omitempty"`
+	Status  string `json:"status,omitempty"`
+}
+
+func loadBranchMeta() (map[string]branchMeta, error) {
+	meta := map[string]branchMeta{}
+	_, err := loadRecord(branchesRef, branchesFile, &meta)
+	return meta, err
+}
+
+func saveBranchMeta(meta map[string]branchMeta) error {
+	return saveRecord(branchesRef, branchesFile, meta, "Update branch metadata")
+}
+
+// metaFor returns the metadata of one branch, empty when it has none.
+func metaFor(branch string) branchMeta {
+	meta, _ := loadBranchMeta()
+	return meta[branch]
+}
+
+func validStatus(s string) bool {
+	for _, status := range applicationStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDate accepts YYYY-MM-DD or 'today'.
+func parseDate(s string) (string, error) {
+	if s == "today" {
+		return time.Now().Format("2006-01-02"), nil
+	}
+	if _, err := time.Parse("2006-01-02", s); err != nil {
+		return "", fmt.Errorf("'%s' is not a date, use YYYY-MM-DD", s)
+	}
+	return s, nil
+}
+
+func (m branchMeta) empty() bool {
+	return m == branchMeta{}
+}
+
+// summary describes the application in one line, e.g.
+// "SRE at Acme [sent 2026-10-01]".
+func (m branchMeta) summary() string {
+	var parts []string
+	switch {
+	case m.Role != "" && m.Company != "":
+		parts = append(parts, fmt.Sprintf("%s at %s", m.Role, m.Company))
+	case m.Role != "":
+		parts = append(parts, m.Role)
+	case m.Company != "":
+		parts = append(parts, m.Company)
+	}
+	if state := strings.TrimSpace(m.Status + " " + m.Date); state != "" {
+		parts = append(parts, "["+state+"]")
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(pickCmd)
+	pickCmd.Flags().StringArray("path", nil, "Field to pick, e.g. experience[0].points[1] (repeatable)")
+}
+
+var pickCmd = &cobra.Command{
+	Use:   "pick <rev>",
+	Short: "Apply selected changes from one commit onto the current branch",
+	Long: `Apply selected field-level changes from one commit onto the current branch.
+
+Name the fields with --path, written as 'mycelium diff' prints them
+(experience[Acme].points[1]) or as JSON pointers (/experience/0/points/1).
+Picking a field takes everything below it too. Without --path the changes
+of the commit are listed so you can choose them by number.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rev := args[0]
+
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		head, err := r.Head()
+		if err != nil || !head.Name().IsBranch() {
+			fmt.Println("[ERROR] Pick needs an active branch. Use 'mycelium branch switch <name>' first.")
+			return
+		}
+		w, _ := r.Worktree()
+		if dirty, _ := resumeDirty(w); dirty {
+			fmt.Println("[WARN] Unsaved changes detected.")
+			fmt.Println("[INFO] Run 'mycelium commit' before picking.")
+			return
+		}
+
+		// 1. Work out what the commit changed
+		source, ok := resolveVersion(r, rev)
+		if !ok {
+			return
+		}
+		parent, _ := source.Parent(0)
+		before, err := treeAt(parent)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read the version before", rev+":", err)
+			return
+		}
+		after, err := treeAt(source)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", rev, err)
+			return
+		}
+		changes := resume.DiffTrees(before, after)
+		if len(changes) == 0 {
+			fmt.Printf("[INFO] %s made no changes to the resume.\n", describeRevision(rev, source.Hash.String()))
+			return
+		}
+
+		// 2. Decide which of them to take
+		raw, _ := cmd.Flags().GetStringArray("path")
+		var selected []resume.Path
+		for _, s := range raw {
+			p, err := resume.ParsePath(s)
+			if err != nil {
+				fmt.Println("[ERROR]", err)
+				return
+			}
+			if err := resume.CheckLabels(p, before, after); err != nil {
+				fmt.Println("[ERROR]", err)
+				return
+			}
+			if !touches(changes, p) {
+				fmt.Printf("[ERROR] %s did not change %s.\n", describeRevision(rev, source.Hash.String()), s)
+				fmt.Printf("[INFO] Run 'mycelium diff %s~1 %s' to see what it changed.\n", rev, rev)
+				return
+			}
+			selected = append(selected, p)
+		}
+		if len(selected) == 0 {
+			if !interactive() {
+				fmt.Println("[ERROR] Name the changes to pick with --path.")
+				return
+			}
+			selected = chooseChanges(changes)
+			if len(selected) == 0 {
+				fmt.Println("[INFO] Nothing picked.")
+				return
+			}
+		}
+
+		// 3. Replay them on top of the current branch
+		ours, _ := r.CommitObject(head.Hash())
+		current, err := treeAt(ours)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read resume.json at HEAD:", err)
+			return
+		}
+		picked, err := resume.Graft(before, after, selected)
+		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+		merged, conflicts := resume.MergeTrees(before, current, picked, nil)
+		if len(conflicts) > 0 {
+			fmt.Printf("[WARN] The picked changes conflict with %s in %d field(s).\n", head.Name().Short(), len(conflicts))
+			if !interactive() {
+				printConflicts(conflicts)
+				fmt.Println("[INFO] Nothing was changed. Run pick in a terminal to resolve them.")
+				os.Exit(1)
+			}
+			merged, _ = resume.MergeTrees(before, current, picked, terminalResolver(len(conflicts)))
+		}
+		applied := resume.DiffTrees(current, merged)
+		if len(applied) == 0 {
+			fmt.Printf("[INFO] %s already has these changes.\n", head.Name().Short())
+			return
+		}
+
+		// 4. Record them
+		res, err := resume.FromTree(merged)
+		if err != nil {
+			fmt.Println("[ERROR] Pick failed:", err)
+			return
+		}
+		if err := res.Save(resume.FileName); err != nil {
+			fmt.Println("[ERROR] Could not write resume.json:", err)
+			return
+		}
+		w.Add(resume.FileName)
+		names := make([]string, len(selected))
+		for i, p := range selected {
+			names[i] = p.String()
+		}
+		message := fmt.Sprintf("Pick %s from %s", strings.Join(names, ", "), source.Hash.String()[:7])
+		hash, err := w.Commit(message, &git.CommitOptions{
+			Author: signature(),
+		})
+		if err != nil {
+			fmt.Println("[ERROR] Commit failed:", err)
+			return
+		}
+		recordMove(r, "pick", message, head.Hash().String())
+
+		palette := newPalette(false)
+		for _, c := range applied {
+			printChange(c, palette)
+		}
+		fmt.Printf("[SUCCESS] Picked %d change(s) onto %s [%s].\n", len(applied), head.Name().Short(), hash.String()[:7])
+	},
+}
+
+// touches reports whether any change lies at, below or above p.
+func touches(changes []resume.Change, p resume.Path) bool {
+	for _, c := range changes {
+		if c.Path.Overlaps(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// chooseChanges lists the changes of a commit and asks which to pick.
+// Moves are left out: picking carries content, not positions.
+func chooseChanges(changes []resume.Change) []resume.Path {
+	var choices []resume.Change
+	for _, c := range changes {
+		if c.Kind != resume.Moved {
+			choices = append(choices, c)
+		}
+	}
+	for i, c := range choices {
+		fmt.Printf("  %2d. [%s] %s\n", i+1, changeTag(c.Path), describeChange(c))
+	}
+	for {
+		answer := ask("Pick which changes? (e.g. 1,3 or 'all', empty for none) ")
+		if answer == "" {
+			return nil
+		}
+		if answer == "all" {
+			paths := make([]resume.Path, len(choices))
+			for i, c := range choices {
+				paths[i] = c.Path
+			}
+			return paths
+		}
+		var paths []resume.Path
+		valid := true
+		for _, field := range strings.Split(answer, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(field))
+			if err != nil || n < 1 || n > len(choices) {
+				fmt.Printf("[ERROR] '%s' is not one of the numbers above.\n", strings.TrimSpace(field))
+				valid = false
+				break
+			}
+			paths = append(paths, choices[n-1].Path)
+		}
+		if valid {
+			return paths
+		}
+	}
+}
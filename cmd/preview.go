@@ -3,7 +3,8 @@ package cmd
 import (
 	"context"
 	"fmt"
-	"os"
+
+	"mycelium/resume"
 
 	"github.com/google/generative-ai-go/genai"
 	"github.com/spf13/cobra"
@@ -32,11 +33,12 @@ var reviewCmd = &cobra.Command{
 		fmt.Printf("[AI] AI Recruiter is analyzing your resume for the role: [%s]...\n", targetRole)
 
 		// 1. Read Resume
-		resumeData, err := os.ReadFile("resume.json")
+		res, err := resume.Load(resume.FileName)
 		if err != nil {
-			fmt.Println("[ERROR] Error: resume.json not found.")
+			fmt.Println("[ERROR] Error: resume.json could not be read:", err)
 			return
 		}
+		resumeData, _ := resume.Marshal(res)
 
 		// 2. Setup Gemini
 		ctx := context.Background()
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+// interactive reports whether a person is at the keyboard to answer prompts.
+// /dev/null is a character device too, so it is ruled out by name.
+func interactive() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	null, err := os.Stat(os.DevNull)
+	return err != nil || !os.SameFile(info, null)
+}
+
+// ask prints a question and returns the trimmed answer. Prompts come
+// before anything is written, so running out of input simply stops.
+func ask(question string) string {
+	fmt.Print(question)
+	line, err := stdin.ReadString('\n')
+	if err == io.EOF && line == "" {
+		fmt.Println()
+		fmt.Println("[ERROR] No answer given, nothing was changed.")
+		os.Exit(1)
+	}
+	return strings.TrimSpace(line)
+}
+
+// confirm asks a yes/no question that defaults to no.
+func confirm(question string) bool {
+	answer := strings.ToLower(ask(question + " [y/N] "))
+	return answer == "y" || answer == "yes"
+}
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"sort"
+	"time"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// resolveCommit turns a hash, branch or tag name into its commit.
+func resolveCommit(r *git.Repository, rev string) (*object.Commit, error) {
+	hash, err := r.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, err
+	}
+	return r.CommitObject(*hash)
+}
+
+// readResumeAt parses resume.json as it was recorded in a commit.
+func readResumeAt(c *object.Commit) (*resume.Resume, error) {
+	file, err := c.File(resume.FileName)
+	if err != nil {
+		return nil, err
+	}
+	data, err := file.Contents()
+	if err != nil {
+		return nil, err
+	}
+	return resume.Parse([]byte(data))
+}
+
+// treeAt returns the resume recorded in a commit as a generic tree, or an
+// empty object for nil, which stands for the state before the first commit.
+func treeAt(c *object.Commit) (interface{}, error) {
+	if c == nil {
+		return resume.NewObject(), nil
+	}
+	r, err := readResumeAt(c)
+	if err != nil {
+		return nil, err
+	}
+	return resume.Tree(r)
+}
+
+// resumeDirty reports whether resume.json has changes that are not committed.
+func resumeDirty(w *git.Worktree) (bool, error) {
+	status, err := w.Status()
+	if err != nil {
+		return false, err
+	}
+	// Status only lists files that differ from HEAD
+	file, ok := status[resume.FileName]
+	if !ok {
+		return false, nil
+	}
+	return file.Worktree != git.Unmodified || file.Staging != git.Unmodified, nil
+}
+
+// commitFile writes a commit on top of parent whose tree is the parent's
+// with resume.json replaced by data, without touching any ref.
+func commitFile(r *git.Repository, parent *object.Commit, data []byte, msg string) (plumbing.Hash, error) {
+	blobHash, err := writeBlob(r, data)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	tree, err := parent.Tree()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	entries := []object.TreeEntry{{Name: resume.FileName, Mode: filemode.Regular, Hash: blobHash}}
+	for _, e := range tree.Entries {
+		if e.Name != resume.FileName {
+			entries = append(entries, e)
+		}
+	}
+	treeHash, err := writeTree(r, entries)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	return writeCommit(r, &object.Commit{
+		Author:       *signature(),
+		Committer:    *signature(),
+		Message:      msg,
+		TreeHash:     treeHash,
+		ParentHashes: []plumbing.Hash{parent.Hash},
+	})
+}
+
+// writeBlob stores a file's content.
+func writeBlob(r *git.Repository, data []byte) (plumbing.Hash, error) {
+	blob := r.Storer.NewEncodedObject()
+	blob.SetType(plumbing.BlobObject)
+	writer, err := blob.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	writer.Write(data)
+	writer.Close()
+	return r.Storer.SetEncodedObject(blob)
+}
+
+// writeTree stores a flat tree, sorting its entries as git requires.
+func writeTree(r *git.Repository, entries []object.TreeEntry) (plumbing.Hash, error) {
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	obj := r.Storer.NewEncodedObject()
+	if err := (&object.Tree{Entries: entries}).Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// writeCommit stores a commit object without touching any ref.
+func writeCommit(r *git.Repository, c *object.Commit) (plumbing.Hash, error) {
+	obj := r.Storer.NewEncodedObject()
+	if err := c.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return r.Storer.SetEncodedObject(obj)
+}
+
+// signature is the author recorded on every commit Mycelium creates.
+func signature() *object.Signature {
+	return &object.Signature{
+		Name:  "mycelium User",
+		Email: "user@mycelium.local",
+		When:  time.Now(),
+	}
+}
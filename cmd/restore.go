@@ -2,57 +2,211 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
+
+	"mycelium/resume"
 
 	"github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/spf13/cobra"
 )
 
 func init() {
 	rootCmd.AddCommand(restoreCmd)
 	restoreCmd.Flags().BoolP("force", "f", false, "Force restore even if there are unsaved changes")
+	restoreCmd.Flags().StringArray("path", nil, "Only restore this part of the resume, e.g. experience[Acme] (repeatable)")
+	restoreCmd.Flags().BoolP("yes", "y", false, "Apply a partial restore without asking")
+	restoreCmd.Flags().Bool("commit", false, "Save the restored content as a new version")
 }
 
 var restoreCmd = &cobra.Command{
 	Use:   "restore [hash]",
 	Short: "Restore resume.json to a previous version",
-	Args:  cobra.ExactArgs(1),
+	Long: `Restore resume.json to a previous version.
+
+The old content is written into resume.json on the current branch, as an
+unsaved change or, with --commit, as a new version. History is never
+rewritten and 'mycelium undo' puts the file back as it was.
+
+With --path only that part of the resume is brought back, e.g. --path projects
+or --path experience[Acme]. The rest of the working file, including unsaved
+edits, is left alone, and the changes are previewed before they are written.`,
+	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		shortHash := args[0]
 		force, _ := cmd.Flags().GetBool("force")
+		commitIt, _ := cmd.Flags().GetBool("commit")
 
-		r, _ := git.PlainOpen(".")
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
 		w, _ := r.Worktree()
 
-		// 1. Resolve Short Hash to Full Hash
-		// This is the fix! It finds the full 40-char ID from your 7-char input
-		fullHash, err := r.ResolveRevision(plumbing.Revision(shortHash))
+		if paths, _ := cmd.Flags().GetStringArray("path"); len(paths) > 0 {
+			yes, _ := cmd.Flags().GetBool("yes")
+			restorePaths(r, shortHash, paths, yes, commitIt)
+			return
+		}
+
+		// 1. Find the version, by hash, branch or tag
+		commit, ok := resolveVersion(r, shortHash)
+		if !ok {
+			return
+		}
+		file, err := commit.File(resume.FileName)
 		if err != nil {
-			fmt.Printf("[ERROR] Could not find version [%s]. Check 'mycelium list' for valid hashes.\n", shortHash)
+			fmt.Printf("[ERROR] Version [%s] has no resume.json.\n", commit.Hash.String()[:7])
+			return
+		}
+		data, err := file.Contents()
+		if err != nil {
+			fmt.Println("[ERROR] Restore failed:", err)
 			return
 		}
 
 		// 2. Safety Check
 		if !force {
-			status, _ := w.Status()
-			if !status.IsClean() {
+			if dirty, _ := resumeDirty(w); dirty {
 				fmt.Println("[WARN] Unsaved changes detected.")
 				fmt.Println("[INFO] Use --force to overwrite: mycelium restore " + shortHash + " --force")
 				return
 			}
 		}
 
-		// 3. Restore
-		err = w.Checkout(&git.CheckoutOptions{
-			Hash:  *fullHash,
-			Force: true,
-		})
-
-		if err != nil {
+		// 3. Write the old content on the current branch
+		short := commit.Hash.String()[:7]
+		summary := fmt.Sprintf("resume.json restored to [%s]", short)
+		message := fmt.Sprintf("Restore resume.json to %s", short)
+		if err := writeRestored(r, []byte(data), summary, message, commitIt); err != nil {
 			fmt.Printf("[ERROR] Restore failed: %v\n", err)
 			return
 		}
 
-		fmt.Printf("[SUCCESS] Network restored to version [%s].\n", fullHash.String()[:7])
+		fmt.Printf("[SUCCESS] Network restored to version [%s].\n", commit.Hash.String()[:7])
+		if !commitIt {
+			fmt.Println("[INFO] Run 'mycelium commit' to save it, or 'mycelium undo' to go back.")
+		}
 	},
 }
+
+// writeRestored replaces resume.json, optionally commits it, and journals
+// the restore so that 'undo' can bring the previous content back.
+func writeRestored(r *git.Repository, data []byte, summary, message string, commitIt bool) error {
+	head, err := r.Head()
+	if commitIt && (err != nil || !head.Name().IsBranch()) {
+		return fmt.Errorf("there is no active branch to commit to; nothing was restored")
+	}
+	entry := journalEntry{Action: "restore", Summary: summary, Written: sha256Hex(data)}
+	if err == nil {
+		entry.Branch = head.Name().Short()
+		entry.Before = head.Hash().String()
+		entry.After = entry.Before
+	}
+
+	previous, err := writeResume(data)
+	if err != nil {
+		return err
+	}
+	entry.Resume = previous
+
+	if commitIt {
+		w, _ := r.Worktree()
+		if _, err := w.Add(resume.FileName); err != nil {
+			return err
+		}
+		hash, err := w.Commit(message, &git.CommitOptions{Author: signature()})
+		if err != nil {
+			return err
+		}
+		entry.After = hash.String()
+		fmt.Printf("[INFO] Saved as version [%s].\n", hash.String()[:7])
+	}
+	record(entry)
+	return nil
+}
+
+// restorePaths grafts parts of an older resume into the working file.
+func restorePaths(r *git.Repository, rev string, raw []string, yes, commitIt bool) {
+	var selected []resume.Path
+	for _, s := range raw {
+		p, err := resume.ParsePath(s)
+		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+		selected = append(selected, p)
+	}
+
+	commit, ok := resolveVersion(r, rev)
+	if !ok {
+		return
+	}
+	old, err := treeAt(commit)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", rev, err)
+		return
+	}
+	res, err := resume.Load(resume.FileName)
+	if err != nil {
+		fmt.Println("[ERROR] Could not read resume.json:", err)
+		return
+	}
+	current, err := resume.Tree(res)
+	if err != nil {
+		fmt.Println("[ERROR] Restore failed:", err)
+		return
+	}
+
+	restored, err := resume.Graft(current, old, selected)
+	if err != nil {
+		fmt.Println("[ERROR]", err)
+		return
+	}
+	changes := resume.DiffTrees(current, restored)
+	if len(changes) == 0 {
+		fmt.Printf("✨ Nothing to restore: %s already matches %s.\n", strings.Join(raw, ", "), describeRevision(rev, commit.Hash.String()))
+		return
+	}
+
+	// Preview before touching the file
+	fmt.Printf("🕰️  Restoring %s from %s:\n", strings.Join(raw, ", "), describeRevision(rev, commit.Hash.String()))
+	fmt.Println("------------------------------------------------------------")
+	palette := newPalette(false)
+	for _, c := range changes {
+		printChange(c, palette)
+	}
+	fmt.Println()
+	if !yes {
+		if !interactive() {
+			fmt.Println("[INFO] Nothing was changed. Re-run with --yes to apply.")
+			return
+		}
+		if !confirm("Apply these changes to resume.json?") {
+			fmt.Println("[INFO] Nothing was changed.")
+			return
+		}
+	}
+
+	out, err := resume.FromTree(restored)
+	if err != nil {
+		fmt.Println("[ERROR] Restore failed:", err)
+		return
+	}
+	data, err := resume.Marshal(out)
+	if err != nil {
+		fmt.Println("[ERROR] Restore failed:", err)
+		return
+	}
+	what, short := strings.Join(raw, ", "), commit.Hash.String()[:7]
+	summary := fmt.Sprintf("%s restored from [%s]", what, short)
+	message := fmt.Sprintf("Restore %s from %s", what, short)
+	if err := writeRestored(r, data, summary, message, commitIt); err != nil {
+		fmt.Println("[ERROR] Restore failed:", err)
+		return
+	}
+	fmt.Printf("[SUCCESS] Restored %d change(s) into resume.json.\n", len(changes))
+	if !commitIt {
+		fmt.Println("[INFO] Run 'mycelium commit' to save them, or 'mycelium undo' to go back.")
+	}
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(revertCmd)
+}
+
+var revertCmd = &cobra.Command{
+	Use:   "revert <rev>",
+	Short: "Undo the changes of one version with a new version",
+	Long: `Undo the changes one version made, recording the result as a new version
+on the current branch. History is kept as it is.
+
+The changes are taken back field by field, so later edits to other jobs,
+bullets or sections survive, and only fields edited again since are
+reported as conflicts.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		rev := args[0]
+
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		head, err := r.Head()
+		if err != nil || !head.Name().IsBranch() {
+			fmt.Println("[ERROR] Revert needs an active branch. Use 'mycelium branch switch <name>' first.")
+			return
+		}
+		w, _ := r.Worktree()
+		if dirty, _ := resumeDirty(w); dirty {
+			fmt.Println("[WARN] Unsaved changes detected.")
+			fmt.Println("[INFO] Run 'mycelium commit' before reverting.")
+			return
+		}
+
+		// 1. Read the version and the one it was made on
+		source, ok := resolveVersion(r, rev)
+		if !ok {
+			return
+		}
+		parent, _ := source.Parent(0)
+		before, err := treeAt(parent)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read the version before", rev+":", err)
+			return
+		}
+		after, err := treeAt(source)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not read resume.json at %s: %v\n", rev, err)
+			return
+		}
+		ours, _ := r.CommitObject(head.Hash())
+		current, err := treeAt(ours)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read resume.json at HEAD:", err)
+			return
+		}
+
+		// 2. Merge the version's undoing into the branch: its own tree is the
+		// common ancestor and its parent the side to apply
+		merged, conflicts := resume.MergeTrees(after, current, before, nil)
+		if len(conflicts) > 0 {
+			fmt.Printf("[WARN] Reverting conflicts with later edits in %d field(s).\n", len(conflicts))
+			if !interactive() {
+				printConflicts(conflicts)
+				fmt.Println("[INFO] Nothing was changed. Run revert in a terminal to resolve them.")
+				os.Exit(1)
+			}
+			merged, _ = resume.MergeTrees(after, current, before, terminalResolver(len(conflicts)))
+		}
+		applied := resume.DiffTrees(current, merged)
+		if len(applied) == 0 {
+			fmt.Printf("[INFO] Nothing to revert: the changes of %s are already gone.\n", describeRevision(rev, source.Hash.String()))
+			return
+		}
+
+		// 3. Record the result
+		res, err := resume.FromTree(merged)
+		if err != nil {
+			fmt.Println("[ERROR] Revert failed:", err)
+			return
+		}
+		if err := res.Save(resume.FileName); err != nil {
+			fmt.Println("[ERROR] Could not write resume.json:", err)
+			return
+		}
+		w.Add(resume.FileName)
+		message := fmt.Sprintf("Revert \"%s\"\n\nThis reverts version %s.", firstLine(source.Message), source.Hash.String())
+		hash, err := w.Commit(message, &git.CommitOptions{
+			Author: signature(),
+		})
+		if err != nil {
+			fmt.Println("[ERROR] Commit failed:", err)
+			return
+		}
+		recordMove(r, "revert", fmt.Sprintf("reverted [%s] %s", source.Hash.String()[:7], firstLine(source.Message)), head.Hash().String())
+
+		palette := newPalette(false)
+		for _, c := range applied {
+			printChange(c, palette)
+		}
+		fmt.Printf("[SUCCESS] Reverted %s [%s].\n", describeRevision(rev, source.Hash.String()), hash.String()[:7])
+	},
+}
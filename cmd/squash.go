@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(squashCmd)
+	squashCmd.Flags().StringP("message", "m", "", "Message for the combined version (default: the messages of the squashed versions)")
+}
+
+var squashCmd = &cobra.Command{
+	Use:   "squash <from>..<to>",
+	Short: "Combine a run of versions into one",
+	Long: `Combine a run of versions on the current branch into a single version,
+e.g. a day of small fixes before tagging the result as sent. As in git,
+<from>..<to> means the versions after <from> up to and including <to>, so
+<from> is the last version to keep as it is. <to> may be left out to squash
+up to the latest version.
+
+Versions made after <to> are kept on top of the combined one. Tags keep
+pointing at the original versions, and 'mycelium undo' brings them back.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		fromRev, toRev, ok := strings.Cut(args[0], "..")
+		if !ok || fromRev == "" {
+			fmt.Println("[ERROR] Name the versions to squash as <from>..<to>, e.g. mycelium squash a1b2c3d..HEAD")
+			return
+		}
+		if toRev == "" {
+			toRev = "HEAD"
+		}
+
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		head, err := r.Head()
+		if err != nil || !head.Name().IsBranch() {
+			fmt.Println("[ERROR] Squash needs an active branch. Use 'mycelium branch switch <name>' first.")
+			return
+		}
+		from, ok := resolveVersion(r, fromRev)
+		if !ok {
+			return
+		}
+		to, ok := resolveVersion(r, toRev)
+		if !ok {
+			return
+		}
+
+		// 1. Find the run on the branch, newest first
+		branch := head.Name().Short()
+		tip, _ := r.CommitObject(head.Hash())
+		var later, squashed []*object.Commit
+		for c := tip; c.Hash != from.Hash; {
+			if len(squashed) > 0 || c.Hash == to.Hash {
+				squashed = append(squashed, c)
+			} else {
+				later = append(later, c)
+			}
+			parent, err := c.Parent(0)
+			if err != nil {
+				fmt.Printf("[ERROR] %s..%s is not a run of versions on %s.\n", fromRev, toRev, branch)
+				fmt.Printf("[INFO] <from> must be an older version than <to>, both on %s; see 'mycelium list'.\n", branch)
+				return
+			}
+			c = parent
+		}
+		if len(squashed) == 0 {
+			fmt.Printf("[ERROR] %s..%s is not a run of versions on %s.\n", fromRev, toRev, branch)
+			fmt.Printf("[INFO] <from> must be an older version than <to>, both on %s; see 'mycelium list'.\n", branch)
+			return
+		}
+		if len(squashed) == 1 {
+			fmt.Println("[INFO] Only one version in that range, nothing to squash.")
+			return
+		}
+		for _, c := range squashed {
+			if c.NumParents() > 1 {
+				fmt.Printf("[ERROR] [%s] is a sync with another branch and cannot be squashed.\n", c.Hash.String()[:7])
+				return
+			}
+		}
+
+		// 2. Write the combined version and replay the later ones onto it
+		msg, _ := cmd.Flags().GetString("message")
+		if msg == "" {
+			lines := make([]string, 0, len(squashed))
+			for i := len(squashed) - 1; i >= 0; i-- {
+				lines = append(lines, firstLine(squashed[i].Message))
+			}
+			msg = strings.Join(lines, "\n")
+		}
+		newTip, err := writeCommit(r, &object.Commit{
+			Author:       *signature(),
+			Committer:    *signature(),
+			Message:      msg,
+			TreeHash:     to.TreeHash,
+			ParentHashes: []plumbing.Hash{from.Hash},
+		})
+		if err != nil {
+			fmt.Println("[ERROR] Squash failed:", err)
+			return
+		}
+		combined := newTip
+		for i := len(later) - 1; i >= 0; i-- {
+			c := later[i]
+			newTip, err = writeCommit(r, &object.Commit{
+				Author:       c.Author,
+				Committer:    *signature(),
+				Message:      c.Message,
+				TreeHash:     c.TreeHash,
+				ParentHashes: append([]plumbing.Hash{newTip}, c.ParentHashes[1:]...),
+			})
+			if err != nil {
+				fmt.Println("[ERROR] Squash failed:", err)
+				return
+			}
+		}
+
+		// 3. Move the branch; its content is unchanged, so resume.json is too
+		if err := r.Storer.SetReference(plumbing.NewHashReference(head.Name(), newTip)); err != nil {
+			fmt.Println("[ERROR] Squash failed:", err)
+			return
+		}
+		recordMove(r, "squash", fmt.Sprintf("squashed %d versions on '%s' into [%s]", len(squashed), branch, combined.String()[:7]), head.Hash().String())
+
+		changes := changeCache{}
+		c, _ := r.CommitObject(combined)
+		fmt.Printf("[SUCCESS] Squashed %d versions into [%s] %s\n", len(squashed), combined.String()[:7], firstLine(msg))
+		fmt.Printf("    %s\n", statLine(changes.of(c)))
+		if len(later) > 0 {
+			fmt.Printf("[INFO] %d later version(s) were kept on top.\n", len(later))
+		}
+		tags := tagsByCommit(r)
+		for _, c := range append(squashed, later...) {
+			for _, name := range tags[c.Hash] {
+				fmt.Printf("[WARN] Tag '%s' still points at the original version [%s].\n", name, c.Hash.String()[:7])
+			}
+		}
+	},
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+)
+
+// firstParentLog returns the messages on the branch, newest first.
+func firstParentLog(t *testing.T, r *git.Repository) []string {
+	t.Helper()
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var log []string
+	for {
+		log = append(log, strings.TrimSpace(c.Message))
+		if c, err = c.Parent(0); err != nil {
+			return log
+		}
+	}
+}
+
+func TestSquashRange(t *testing.T) {
+	// Versions are named by their index in v1..v4; -1 leaves <to> out
+	tests := []struct {
+		name     string
+		from, to int
+		want     []string
+	}{
+		{"from is kept, to is squashed", 0, 2, []string{"v4", "v2\nv3", "v1"}},
+		{"to defaults to the latest version", 0, -1, []string{"v2\nv3\nv4", "v1"}},
+		{"run in the middle", 1, 3, []string{"v3\nv4", "v2", "v1"}},
+		{"single version is left alone", 1, 2, []string{"v4", "v3", "v2", "v1"}},
+		{"reversed range is refused", 2, 0, []string{"v4", "v3", "v2", "v1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, hashes := testRepo(t, "v1", "v2", "v3", "v4")
+			arg := hashes[tt.from].String() + ".."
+			if tt.to >= 0 {
+				arg += hashes[tt.to].String()
+			}
+			squashCmd.Run(squashCmd, []string{arg})
+
+			if got := firstParentLog(t, r); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("history = %q, want %q", got, tt.want)
+			}
+			if name := workingName(t); name != "v4" {
+				t.Errorf("working name = %s, want v4", name)
+			}
+		})
+	}
+}
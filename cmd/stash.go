@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+// stashPrefix namespaces the refs that hold stashed edits. Each stash is a
+// commit whose parent is the version the edits were made on.
+const stashPrefix = "refs/mycelium/stash/"
+
+func init() {
+	rootCmd.AddCommand(stashCmd)
+	stashCmd.AddCommand(stashPushCmd)
+	stashCmd.AddCommand(stashPopCmd)
+	stashCmd.AddCommand(stashListCmd)
+	stashCmd.AddCommand(stashDropCmd)
+	stashCmd.Flags().StringP("message", "m", "", "Describe the stashed edits")
+	stashPushCmd.Flags().StringP("message", "m", "", "Describe the stashed edits")
+}
+
+var stashCmd = &cobra.Command{
+	Use:   "stash",
+	Short: "Park unfinished edits to resume.json",
+	Long: `Park unfinished edits to resume.json so the branch can be switched, and
+bring them back later with 'stash pop'. Without a subcommand, stash pushes.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		stashPushCmd.Run(cmd, args)
+	},
+}
+
+var stashPushCmd = &cobra.Command{
+	Use:   "push",
+	Short: "Stash the unsaved edits and reset resume.json",
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		msg, _ := cmd.Flags().GetString("message")
+		hash, err := stashPush(r, msg)
+		if err != nil {
+			fmt.Println("[ERROR] Stash failed:", err)
+			return
+		}
+		fmt.Printf("📦 Edits stashed as stash@{0} [%s].\n", hash)
+	},
+}
+
+var stashPopCmd = &cobra.Command{
+	Use:   "pop [stash]",
+	Short: "Bring stashed edits back into resume.json",
+	Long: `Bring stashed edits back into resume.json and drop the stash. The edits are
+merged field by field into the current file, so they can be popped onto
+another branch or on top of new edits.`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		stash, ok := findStash(r, args)
+		if !ok {
+			return
+		}
+		c, err := r.CommitObject(stash.Hash())
+		if err != nil {
+			fmt.Println("[ERROR] Could not read the stash:", err)
+			return
+		}
+		parent, _ := c.Parent(0)
+		base, err := treeAt(parent)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read the stash:", err)
+			return
+		}
+		theirs, err := treeAt(c)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read the stash:", err)
+			return
+		}
+		data, err := os.ReadFile(resume.FileName)
+		if err != nil {
+			fmt.Println("[ERROR] Could not read resume.json:", err)
+			return
+		}
+		ours, err := resume.DecodeTree(data)
+		if err != nil {
+			fmt.Println("[ERROR] resume.json is not valid JSON:", err)
+			return
+		}
+
+		merged, conflicts := resume.MergeTrees(base, ours, theirs, nil)
+		if len(conflicts) > 0 {
+			fmt.Printf("[WARN] The stash conflicts with resume.json in %d field(s).\n", len(conflicts))
+			if !interactive() {
+				printConflicts(conflicts)
+				fmt.Println("[INFO] Nothing was changed. Run stash pop in a terminal to resolve them.")
+				os.Exit(1)
+			}
+			merged, _ = resume.MergeTrees(base, ours, theirs, terminalResolver(len(conflicts)))
+		}
+		res, err := resume.FromTree(merged)
+		if err != nil {
+			fmt.Println("[ERROR] Pop failed:", err)
+			return
+		}
+		if err := res.Save(resume.FileName); err != nil {
+			fmt.Println("[ERROR] Could not write resume.json:", err)
+			return
+		}
+		r.Storer.RemoveReference(stash.Name())
+		fmt.Printf("📦 Stashed edits restored: %s\n", firstLine(c.Message))
+	},
+}
+
+var stashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List stashed edits",
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		stashes, _ := listStashes(r)
+		if len(stashes) == 0 {
+			fmt.Println("No stashed edits.")
+			return
+		}
+		changes := changeCache{}
+		for i, ref := range stashes {
+			c, err := r.CommitObject(ref.Hash())
+			if err != nil {
+				continue
+			}
+			fmt.Printf("stash@{%d}: %s (%s)\n", i, firstLine(c.Message), c.Author.When.Format("2006-01-02 15:04"))
+			fmt.Printf("    %s\n", statLine(changes.of(c)))
+		}
+	},
+}
+
+var stashDropCmd = &cobra.Command{
+	Use:   "drop [stash]",
+	Short: "Discard stashed edits",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		stash, ok := findStash(r, args)
+		if !ok {
+			return
+		}
+		if err := r.Storer.RemoveReference(stash.Name()); err != nil {
+			fmt.Println("[ERROR] Could not drop the stash:", err)
+			return
+		}
+		fmt.Printf("🗑️  Dropped stash [%s].\n", stash.Hash().String()[:7])
+	},
+}
+
+// stashPush records the working resume.json as a stash commit on top of
+// HEAD, then puts the file back as HEAD has it.
+func stashPush(r *git.Repository, msg string) (string, error) {
+	w, _ := r.Worktree()
+	if dirty, _ := resumeDirty(w); !dirty {
+		return "", fmt.Errorf("no unsaved changes to stash")
+	}
+	head, err := r.Head()
+	if err != nil {
+		return "", fmt.Errorf("commit once before stashing")
+	}
+	base, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(resume.FileName)
+	if err != nil {
+		return "", err
+	}
+
+	if msg == "" {
+		msg = "WIP"
+	}
+	hash, err := commitFile(r, base, data, fmt.Sprintf("On %s: %s", head.Name().Short(), msg))
+	if err != nil {
+		return "", err
+	}
+	stashes, _ := listStashes(r)
+	next := 0
+	for _, ref := range stashes {
+		if n, err := strconv.Atoi(strings.TrimPrefix(ref.Name().String(), stashPrefix)); err == nil {
+			next = max(next, n+1)
+		}
+	}
+	name := plumbing.ReferenceName(stashPrefix + strconv.Itoa(next))
+	if err := r.Storer.SetReference(plumbing.NewHashReference(name, hash)); err != nil {
+		return "", err
+	}
+
+	// Put resume.json back as the stashed-from version has it, leaving
+	// anything else the user staged alone
+	if err := w.Reset(&git.ResetOptions{Commit: base.Hash, Mode: git.MixedReset, Files: []string{resume.FileName}}); err != nil {
+		return "", err
+	}
+	file, err := base.File(resume.FileName)
+	if err != nil {
+		return "", err
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return "", err
+	}
+	if _, err := writeResume([]byte(content)); err != nil {
+		return "", err
+	}
+	return hash.String()[:7], nil
+}
+
+// listStashes returns the stash refs, newest first.
+func listStashes(r *git.Repository) ([]*plumbing.Reference, error) {
+	iter, err := r.References()
+	if err != nil {
+		return nil, err
+	}
+	var refs []*plumbing.Reference
+	iter.ForEach(func(ref *plumbing.Reference) error {
+		if strings.HasPrefix(ref.Name().String(), stashPrefix) {
+			refs = append(refs, ref)
+		}
+		return nil
+	})
+	number := func(ref *plumbing.Reference) int {
+		n, _ := strconv.Atoi(strings.TrimPrefix(ref.Name().String(), stashPrefix))
+		return n
+	}
+	sort.Slice(refs, func(i, j int) bool { return number(refs[i]) > number(refs[j]) })
+	return refs, nil
+}
+
+// findStash picks the stash named by args (0, 1, stash@{2}...) or the
+// newest one, printing the problem when there is none.
+func findStash(r *git.Repository, args []string) (*plumbing.Reference, bool) {
+	stashes, _ := listStashes(r)
+	if len(stashes) == 0 {
+		fmt.Println("[INFO] No stashed edits.")
+		return nil, false
+	}
+	if len(args) == 0 {
+		return stashes[0], true
+	}
+	arg := strings.TrimSuffix(strings.TrimPrefix(args[0], "stash@{"), "}")
+	i, err := strconv.Atoi(arg)
+	if err != nil || i < 0 || i >= len(stashes) {
+		fmt.Printf("[ERROR] No stash '%s'. See 'mycelium stash list'.\n", args[0])
+		return nil, false
+	}
+	return stashes[i], true
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+)
+
+func TestStashPushKeepsOtherStagedFiles(t *testing.T) {
+	r, _ := testRepo(t, "v1")
+	w, _ := r.Worktree()
+	if err := os.WriteFile("notes.txt", []byte("call Acme back\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("notes.txt"); err != nil {
+		t.Fatal(err)
+	}
+	writeName(t, "draft")
+	if _, err := w.Add(resume.FileName); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := stashPush(r, "draft"); err != nil {
+		t.Fatal(err)
+	}
+	if name := workingName(t); name != "v1" {
+		t.Errorf("name after stash = %s, want v1", name)
+	}
+	status, err := w.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s, changed := status[resume.FileName]; changed {
+		t.Errorf("%s left changed after stash: %c%c", resume.FileName, s.Staging, s.Worktree)
+	}
+	if s := status.File("notes.txt"); s.Staging != git.Added {
+		t.Errorf("notes.txt unstaged by stash: %c%c", s.Staging, s.Worktree)
+	}
+}
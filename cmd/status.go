@@ -25,8 +25,20 @@ var statusCmd = &cobra.Command{
 		ref, err := r.Head()
 		if err != nil {
 			fmt.Println("📍 Current Branch: (initial branch)")
+		} else if !ref.Name().IsBranch() {
+			fmt.Printf("📍 No active branch: viewing version [%s]\n", ref.Hash().String()[:7])
+			fmt.Println("[INFO] Run 'mycelium branch create <name>' to keep working from here, or switch to a branch.")
 		} else {
 			fmt.Printf("📍 Current Branch: %s\n", ref.Name().Short())
+			if meta := metaFor(ref.Name().Short()); !meta.empty() {
+				fmt.Printf("🏢 Application: %s\n", meta.summary())
+			}
+		}
+
+		var merge mergeState
+		if ok, _ := loadState(mergeStateFile, &merge); ok {
+			fmt.Printf("[WARN] Sync with %s is waiting for conflicts to be resolved.\n", merge.Target)
+			fmt.Println("[INFO] Run 'mycelium edit' to resolve them, or 'mycelium sync --abort'.")
 		}
 
 		// 2. Check for changes
@@ -37,7 +49,7 @@ var statusCmd = &cobra.Command{
 			fmt.Println("[INFO] Mycelium network is healthy and synchronized.")
 		} else {
 			// Check if it's a real change or just a timestamp change
-			if status["resume.json"].Worktree == git.Unmodified {
+			if dirty, _ := resumeDirty(w); !dirty {
 				fmt.Println("[INFO] Mycelium network is healthy (metadata changes ignored).")
 			} else {
 				fmt.Println("[WARN] Uncommitted changes detected in the network.")
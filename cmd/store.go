@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// resolveVersion finds the commit a hash, branch or tag names, and tells
+// the user where to look when there is none.
+func resolveVersion(r *git.Repository, rev string) (*object.Commit, bool) {
+	c, err := resolveCommit(r, rev)
+	if err != nil {
+		fmt.Printf("[ERROR] Could not find version [%s]. Check 'mycelium list' or 'mycelium tag list' for valid versions.\n", rev)
+		return nil, false
+	}
+	return c, true
+}
+
+// Mycelium keeps its own bookkeeping inside .git so that it never shows up
+// as a change to the resume. Files under .git/mycelium are local to this
+// clone, which suits working state such as the undo journal; records that
+// belong with the history are kept on refs instead, see loadRecord.
+var stateDir = filepath.Join(".git", "mycelium")
+
+// loadState reads a state file into v and reports whether it exists.
+func loadState(name string, v interface{}) (bool, error) {
+	data, err := os.ReadFile(filepath.Join(stateDir, name))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(data, v)
+}
+
+func saveState(name string, v interface{}) error {
+	if err := os.MkdirAll(stateDir, 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(stateDir, name), data, 0644)
+}
+
+func clearState(name string) error {
+	err := os.Remove(filepath.Join(stateDir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
+
+// loadRecord reads the JSON file a record ref such as refs/mycelium/meta
+// holds into v and reports whether it exists. Records saved before they
+// moved onto refs are still read from .git/mycelium.
+func loadRecord(ref, name string, v interface{}) (bool, error) {
+	r, err := git.PlainOpen(".")
+	if err != nil {
+		return false, err
+	}
+	head, err := r.Reference(plumbing.ReferenceName(ref), true)
+	if err == plumbing.ErrReferenceNotFound {
+		return loadState(name, v)
+	}
+	if err != nil {
+		return false, err
+	}
+	c, err := r.CommitObject(head.Hash())
+	if err != nil {
+		return false, err
+	}
+	file, err := c.File(name)
+	if err != nil {
+		return false, err
+	}
+	data, err := file.Contents()
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal([]byte(data), v)
+}
+
+// saveRecord commits v as the JSON file name on ref, on top of the
+// previous save. Being ordinary git objects, records can be pushed and
+// fetched with the ref, e.g. git push origin refs/mycelium/meta.
+func saveRecord(ref, name string, v interface{}, msg string) error {
+	r, err := git.PlainOpen(".")
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	blob, err := writeBlob(r, append(data, '\n'))
+	if err != nil {
+		return err
+	}
+	tree, err := writeTree(r, []object.TreeEntry{{Name: name, Mode: filemode.Regular, Hash: blob}})
+	if err != nil {
+		return err
+	}
+	var parents []plumbing.Hash
+	if prev, err := r.Reference(plumbing.ReferenceName(ref), true); err == nil {
+		parents = append(parents, prev.Hash())
+	}
+	hash, err := writeCommit(r, &object.Commit{
+		Author:       *signature(),
+		Committer:    *signature(),
+		Message:      msg,
+		TreeHash:     tree,
+		ParentHashes: parents,
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.Storer.SetReference(plumbing.NewHashReference(plumbing.ReferenceName(ref), hash)); err != nil {
+		return err
+	}
+	return clearState(name)
+}
@@ -2,41 +2,365 @@ package cmd
 
 import (
 	"fmt"
-	"os/exec"
+	"os"
+
+	"mycelium/render"
+	"mycelium/resume"
 
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/spf13/cobra"
 )
 
+// mergeStateFile holds a sync that is waiting for conflicts to be resolved
+// in the editor.
+const mergeStateFile = "MERGE.json"
+
+type mergeState struct {
+	Branch string `json:"branch"`
+	Target string `json:"target"`
+	Base   string `json:"base"`
+	Ours   string `json:"ours"`
+	Theirs string `json:"theirs"`
+}
+
 func init() {
 	rootCmd.AddCommand(syncCmd)
+	syncCmd.Flags().Bool("web", false, "Resolve conflicts in the editor instead of the terminal")
+	syncCmd.Flags().Bool("abort", false, "Abandon a sync that is waiting for conflicts to be resolved")
 }
 
 var syncCmd = &cobra.Command{
 	Use:   "sync [branch]",
-	Short: "Sync (rebase) current branch with another branch",
-	Args:  cobra.ExactArgs(1),
+	Short: "Merge updates from another branch into the current branch",
+	Long: `Merge updates from another branch into the current branch.
+
+The merge works on resume fields rather than lines: edits to different jobs,
+bullets or sections are combined automatically, and only fields changed in
+different ways on both branches are reported as conflicts. Conflicts are
+resolved one by one in the terminal, or in the editor with --web.`,
+	Args: cobra.RangeArgs(0, 1),
 	Run: func(cmd *cobra.Command, args []string) {
+		if abort, _ := cmd.Flags().GetBool("abort"); abort {
+			var state mergeState
+			if ok, _ := loadState(mergeStateFile, &state); !ok {
+				fmt.Println("[INFO] No sync in progress.")
+				return
+			}
+			clearState(mergeStateFile)
+			fmt.Printf("[SUCCESS] Sync of %s with %s abandoned.\n", state.Branch, state.Target)
+			return
+		}
+		if len(args) == 0 {
+			fmt.Println("[ERROR] Please name the branch to sync with: mycelium sync main")
+			return
+		}
 		targetBranch := args[0]
 
-		r, _ := git.PlainOpen(".")
-		head, _ := r.Head()
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		head, err := r.Head()
+		if err != nil || !head.Name().IsBranch() {
+			fmt.Println("[ERROR] Sync needs an active branch. Use 'mycelium branch switch <name>' first.")
+			return
+		}
 		currentBranch := head.Name().Short()
 
+		w, _ := r.Worktree()
+		if dirty, _ := resumeDirty(w); dirty {
+			fmt.Println("[WARN] Unsaved changes detected.")
+			fmt.Println("[INFO] Run 'mycelium commit' before syncing.")
+			return
+		}
+
+		ours, _ := r.CommitObject(head.Hash())
+		theirs, err := resolveCommit(r, targetBranch)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not find branch [%s].\n", targetBranch)
+			return
+		}
+
 		fmt.Printf("[INFO] Syncing %s with updates from %s...\n", currentBranch, targetBranch)
 
-		// Programmatic rebase is extremely complex in go-git,
-		// so for a production-ready tool, we wrap the git CLI
-		// to handle complex merge conflicts safely.
-		out, err := exec.Command("git", "rebase", targetBranch).CombinedOutput()
+		// 1. Find the common ancestor
+		bases, err := ours.MergeBase(theirs)
+		if err != nil || len(bases) == 0 {
+			fmt.Printf("[ERROR] %s and %s share no history.\n", currentBranch, targetBranch)
+			return
+		}
+		base := bases[0]
+
+		if base.Hash == theirs.Hash {
+			fmt.Printf("[SUCCESS] %s is already up-to-date with %s.\n", currentBranch, targetBranch)
+			return
+		}
+		if base.Hash == ours.Hash {
+			err := w.Reset(&git.ResetOptions{Commit: theirs.Hash, Mode: git.HardReset})
+			if err != nil {
+				fmt.Println("[ERROR] Sync failed:", err)
+				return
+			}
+			recordMove(r, "sync", fmt.Sprintf("%s fast-forwarded to %s", currentBranch, targetBranch), ours.Hash.String())
+			fmt.Printf("[SUCCESS] %s fast-forwarded to %s [%s].\n", currentBranch, targetBranch, theirs.Hash.String()[:7])
+			return
+		}
+
+		// 2. Merge the three versions field by field
+		state := mergeState{
+			Branch: currentBranch,
+			Target: targetBranch,
+			Base:   base.Hash.String(),
+			Ours:   ours.Hash.String(),
+			Theirs: theirs.Hash.String(),
+		}
+		versions, err := state.versions(r)
+		if err != nil {
+			fmt.Println("[ERROR]", err)
+			return
+		}
+		merged, conflicts, err := resume.Merge(versions[0], versions[1], versions[2], nil)
+		if err != nil {
+			fmt.Println("[ERROR] Sync failed:", err)
+			return
+		}
+
+		// 3. Let the user settle every conflict
+		if len(conflicts) > 0 {
+			fmt.Printf("[WARN] Sync conflict detected in %d field(s).\n", len(conflicts))
+			web, _ := cmd.Flags().GetBool("web")
+			switch {
+			case web:
+				if err := saveState(mergeStateFile, state); err != nil {
+					fmt.Println("[ERROR] Could not save the sync:", err)
+					return
+				}
+				fmt.Println("[INFO] Resolve the conflicts in the ⚠️ tab of the editor.")
+				serveEditor(render.DefaultTemplate)
+				return
+			case interactive():
+				merged, _, err = resume.Merge(versions[0], versions[1], versions[2], terminalResolver(len(conflicts)))
+				if err != nil {
+					fmt.Println("[ERROR] Sync failed:", err)
+					return
+				}
+			default:
+				printConflicts(conflicts)
+				fmt.Println("[INFO] Nothing was changed. Run sync in a terminal to resolve them, or use --web.")
+				os.Exit(1)
+			}
+		}
 
+		// 4. Record the merge
+		commit, err := state.commit(r, merged)
 		if err != nil {
-			fmt.Println("[ERROR] Sync conflict detected!")
-			fmt.Println(string(out))
-			fmt.Println("[INFO] Use standard git tools to resolve conflicts, or 'git rebase --abort'.")
+			fmt.Println("[ERROR] Failed to record the sync:", err)
 			return
 		}
 
-		fmt.Printf("[SUCCESS] %s is now up-to-date with %s.\n", currentBranch, targetBranch)
+		fmt.Printf("[SUCCESS] %s is now up-to-date with %s [%s].\n", currentBranch, targetBranch, commit.String()[:7])
 	},
 }
+
+// versions reads the base, ours and theirs resumes of a sync.
+func (s mergeState) versions(r *git.Repository) ([3]*resume.Resume, error) {
+	var out [3]*resume.Resume
+	for i, hash := range []string{s.Base, s.Ours, s.Theirs} {
+		c, err := r.CommitObject(plumbing.NewHash(hash))
+		if err != nil {
+			return out, fmt.Errorf("could not find version [%s]", hash[:7])
+		}
+		out[i], err = readResumeAt(c)
+		if err != nil {
+			return out, fmt.Errorf("could not read resume.json at %s: %v", hash[:7], err)
+		}
+	}
+	return out, nil
+}
+
+// commit writes the merged resume and records it with both branches as
+// parents.
+func (s mergeState) commit(r *git.Repository, merged *resume.Resume) (plumbing.Hash, error) {
+	head, err := r.Head()
+	if err != nil || head.Hash().String() != s.Ours || head.Name().Short() != s.Branch {
+		return plumbing.ZeroHash, fmt.Errorf("%s has moved since the sync started, run it again", s.Branch)
+	}
+	w, _ := r.Worktree()
+	if err := merged.Save(resume.FileName); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := w.Add(resume.FileName); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	hash, err := w.Commit(fmt.Sprintf("Sync %s with %s", s.Branch, s.Target), &git.CommitOptions{
+		Author:  signature(),
+		Parents: []plumbing.Hash{plumbing.NewHash(s.Ours), plumbing.NewHash(s.Theirs)},
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	recordMove(r, "sync", fmt.Sprintf("%s synced with %s", s.Branch, s.Target), s.Ours)
+	return hash, nil
+}
+
+// resolution is the user's decision for one conflict: keep ours, theirs,
+// base or both, or replace the field with an edited value.
+type resolution struct {
+	Choice string `json:"choice"`
+	Value  string `json:"value,omitempty"`
+}
+
+func (res resolution) values(c resume.Conflict) ([]interface{}, error) {
+	switch res.Choice {
+	case "ours":
+		return keep(c.Ours), nil
+	case "theirs":
+		return keep(c.Theirs), nil
+	case "base":
+		return keep(c.Base), nil
+	case "both":
+		if !c.Entry {
+			return nil, fmt.Errorf("%s is a single field, both versions cannot be kept", c.Path)
+		}
+		return keep(c.Ours, c.Theirs), nil
+	case "edit":
+		return editedValue(c, res.Value)
+	}
+	return nil, fmt.Errorf("unknown choice '%s' for %s", res.Choice, c.Path)
+}
+
+// editedValue reads a hand-written value: plain text for text fields, JSON
+// for anything else.
+func editedValue(c resume.Conflict, text string) ([]interface{}, error) {
+	isText := true
+	for _, v := range []interface{}{c.Base, c.Ours, c.Theirs} {
+		if _, ok := v.(string); v != nil && !ok {
+			isText = false
+		}
+	}
+	if isText {
+		if text == "" && c.Entry {
+			return nil, nil
+		}
+		return []interface{}{text}, nil
+	}
+	v, err := resume.DecodeTree([]byte(text))
+	if err != nil {
+		return nil, fmt.Errorf("%s needs a JSON value: %v", c.Path, err)
+	}
+	return keep(v), nil
+}
+
+func keep(values ...interface{}) []interface{} {
+	var out []interface{}
+	for _, v := range values {
+		if v != nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// terminalResolver asks about each conflict in turn on the terminal.
+func terminalResolver(total int) resume.Resolver {
+	n := 0
+	return func(c resume.Conflict) []interface{} {
+		n++
+		fmt.Printf("\n[CONFLICT %d/%d] %s\n", n, total, c.Path)
+		printConflictValues(c)
+
+		options := "(o)urs, (t)heirs, (b)ase, (e)dit"
+		if c.Entry {
+			options += ", keep bot(h)"
+		}
+		choices := map[string]string{"o": "ours", "t": "theirs", "b": "base", "e": "edit", "h": "both"}
+		for {
+			answer := ask("Keep " + options + "? ")
+			choice, ok := choices[answer]
+			if !ok {
+				choice = answer
+			}
+			res := resolution{Choice: choice}
+			if choice == "edit" {
+				res.Value = ask("New value: ")
+			}
+			values, err := res.values(c)
+			if err != nil {
+				fmt.Println("[ERROR]", err)
+				continue
+			}
+			return values
+		}
+	}
+}
+
+func printConflicts(conflicts []resume.Conflict) {
+	for _, c := range conflicts {
+		fmt.Printf("[CONFLICT] %s\n", c.Path)
+		printConflictValues(c)
+	}
+}
+
+func printConflictValues(c resume.Conflict) {
+	fmt.Printf("    base:   %s\n", conflictValue(c.Base))
+	fmt.Printf("    ours:   %s\n", conflictValue(c.Ours))
+	fmt.Printf("    theirs: %s\n", conflictValue(c.Theirs))
+}
+
+func conflictValue(v interface{}) string {
+	if v == nil {
+		return "(removed)"
+	}
+	return summarize(v)
+}
+
+// pendingMerge loads a sync waiting for the editor, with its conflicts.
+func pendingMerge(r *git.Repository) (*mergeState, []resume.Conflict, error) {
+	var state mergeState
+	if ok, err := loadState(mergeStateFile, &state); !ok || err != nil {
+		return nil, nil, err
+	}
+	versions, err := state.versions(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	_, conflicts, err := resume.Merge(versions[0], versions[1], versions[2], nil)
+	return &state, conflicts, err
+}
+
+// resolveMerge completes a pending sync with one resolution per conflict,
+// in the order the conflicts were reported.
+func resolveMerge(r *git.Repository, state *mergeState, resolutions []resolution) (plumbing.Hash, error) {
+	versions, err := state.versions(r)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	var failed error
+	n := 0
+	merged, _, err := resume.Merge(versions[0], versions[1], versions[2], func(c resume.Conflict) []interface{} {
+		defer func() { n++ }()
+		if n >= len(resolutions) {
+			failed = fmt.Errorf("no decision for %s", c.Path)
+			return keep(c.Ours)
+		}
+		values, err := resolutions[n].values(c)
+		if err != nil && failed == nil {
+			failed = err
+		}
+		return values
+	})
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if failed != nil {
+		return plumbing.ZeroHash, failed
+	}
+	hash, err := state.commit(r, merged)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	clearState(mergeStateFile)
+	return hash, nil
+}
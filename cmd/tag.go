@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagListCmd)
+	tagCmd.AddCommand(tagDeleteCmd)
+	tagCmd.Flags().StringP("message", "m", "", "Describe the tagged version")
+	tagCmd.Flags().BoolP("force", "f", false, "Move the tag if it already exists")
+}
+
+var tagCmd = &cobra.Command{
+	Use:   "tag [name] [rev]",
+	Short: "Give a resume version a stable name",
+	Long: `Give a resume version a stable name, such as sent-to-stripe-2026-09.
+
+The tag points at rev, or at the current version when rev is omitted, and
+can be used anywhere a hash is accepted: diff, restore, pick, sync and so
+on. Without a name the existing tags are listed.`,
+	Args: cobra.MaximumNArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) == 0 {
+			tagListCmd.Run(cmd, args)
+			return
+		}
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+
+		name, rev := args[0], "HEAD"
+		if len(args) > 1 {
+			rev = args[1]
+		}
+		if err := plumbing.NewTagReferenceName(name).Validate(); err != nil {
+			fmt.Printf("[ERROR] '%s' is not a valid tag name.\n", name)
+			return
+		}
+		commit, ok := resolveVersion(r, rev)
+		if !ok {
+			return
+		}
+
+		if _, err := r.Tag(name); err == nil {
+			if force, _ := cmd.Flags().GetBool("force"); !force {
+				fmt.Printf("[ERROR] Tag '%s' already exists. Use -f to move it.\n", name)
+				return
+			}
+			r.DeleteTag(name)
+		}
+
+		message, _ := cmd.Flags().GetString("message")
+		if message == "" {
+			message = name
+		}
+		_, err = r.CreateTag(name, commit.Hash, &git.CreateTagOptions{
+			Tagger:  signature(),
+			Message: message,
+		})
+		if err != nil {
+			fmt.Println("[ERROR] Could not create tag:", err)
+			return
+		}
+		fmt.Printf("🏷️  Tagged [%s] as '%s'.\n", commit.Hash.String()[:7], name)
+	},
+}
+
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tagged versions",
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		tags, err := listTags(r)
+		if err != nil || len(tags) == 0 {
+			fmt.Println("No tags yet. Name a version with 'mycelium tag <name>'.")
+			return
+		}
+
+		width := 0
+		for _, t := range tags {
+			width = max(width, len(t.Name))
+		}
+		fmt.Println("🏷️  TAGS:")
+		fmt.Println("-------------------")
+		for _, t := range tags {
+			fmt.Printf("%-*s [%s] %s (%s)\n", width, t.Name, t.Commit.Hash.String()[:7], t.Message, t.When.Format("2006-01-02"))
+		}
+	},
+}
+
+var tagDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "Delete a tag",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		if err := r.DeleteTag(args[0]); err != nil {
+			fmt.Printf("[ERROR] Tag '%s' does not exist.\n", args[0])
+			return
+		}
+		fmt.Printf("🗑️  Tag '%s' deleted.\n", args[0])
+	},
+}
+
+type versionTag struct {
+	Name    string
+	Message string
+	When    time.Time
+	Commit  *object.Commit
+}
+
+// listTags returns every tag with the commit it names, sorted by name.
+// Lightweight tags made by plain git are included and use the commit's
+// own message and date.
+func listTags(r *git.Repository) ([]versionTag, error) {
+	iter, err := r.Tags()
+	if err != nil {
+		return nil, err
+	}
+	var tags []versionTag
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		t := versionTag{Name: ref.Name().Short()}
+		if annotated, err := r.TagObject(ref.Hash()); err == nil {
+			t.Message, t.When = firstLine(annotated.Message), annotated.Tagger.When
+			t.Commit, err = annotated.Commit()
+			if err != nil {
+				return nil
+			}
+		} else if c, err := r.CommitObject(ref.Hash()); err == nil {
+			t.Commit, t.Message, t.When = c, firstLine(c.Message), c.Author.When
+		} else {
+			return nil
+		}
+		tags = append(tags, t)
+		return nil
+	})
+	sort.Slice(tags, func(i, j int) bool { return tags[i].Name < tags[j].Name })
+	return tags, err
+}
+
+// tagsByCommit maps commit hashes to the names of the tags pointing at them.
+func tagsByCommit(r *git.Repository) map[plumbing.Hash][]string {
+	out := map[plumbing.Hash][]string{}
+	tags, _ := listTags(r)
+	for _, t := range tags {
+		out[t.Commit.Hash] = append(out[t.Commit.Hash], t.Name)
+	}
+	return out
+}
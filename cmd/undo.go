@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+	undoCmd.Flags().Bool("list", false, "Show the actions that can be undone")
+	undoCmd.Flags().BoolP("force", "f", false, "Undo even if resume.json was edited since")
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Reverse the last restore, commit, sync, pick, squash or branch switch",
+	Long: `Reverse the most recent action recorded in the journal: a restore, a
+commit or amend, a sync, a pick, a revert, a squash or a branch switch.
+Undoing a commit or amend keeps its changes in resume.json as unsaved
+edits. Run it again to step further back.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		r, err := git.PlainOpen(".")
+		if err != nil {
+			fmt.Println("[ERROR] Not a mycelium repo. Run 'mycelium init'")
+			return
+		}
+		entries, err := loadJournal()
+		if err != nil {
+			fmt.Println("[ERROR] Could not read the journal:", err)
+			return
+		}
+		if len(entries) == 0 {
+			fmt.Println("[INFO] Nothing to undo.")
+			return
+		}
+
+		if list, _ := cmd.Flags().GetBool("list"); list {
+			fmt.Println("↩️  UNDO JOURNAL (most recent first):")
+			fmt.Println("-------------------")
+			for i := len(entries) - 1; i >= 0; i-- {
+				e := entries[i]
+				fmt.Printf("%s  %-8s %s\n", e.When.Format("2006-01-02 15:04"), e.Action, e.Summary)
+			}
+			return
+		}
+
+		last := entries[len(entries)-1]
+		force, _ := cmd.Flags().GetBool("force")
+		if err := undo(r, last, force); err != nil {
+			fmt.Println("[ERROR] Cannot undo", last.Action+":", err)
+			return
+		}
+		saveJournal(entries[:len(entries)-1])
+		fmt.Printf("[SUCCESS] Undid %s: %s\n", last.Action, last.Summary)
+	},
+}
+
+// undo reverses one journal entry, refusing when later work would be lost.
+func undo(r *git.Repository, e journalEntry, force bool) error {
+	w, _ := r.Worktree()
+	head, err := r.Head()
+	if err != nil {
+		return fmt.Errorf("no version is checked out")
+	}
+
+	if e.Action == "switch" {
+		if head.Name().Short() != e.Target {
+			return fmt.Errorf("you are no longer on '%s'", e.Target)
+		}
+		if dirty, _ := resumeDirty(w); dirty {
+			return fmt.Errorf("resume.json has unsaved changes, commit them first")
+		}
+		return w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(e.Branch)})
+	}
+
+	// Every other action moved a branch tip, rewrote resume.json, or both
+	if head.Name().Short() != e.Branch || head.Hash().String() != e.After {
+		return fmt.Errorf("'%s' has moved on since, undo the later actions first", e.Branch)
+	}
+	if e.Written != "" && !force {
+		if sum, err := fileSHA256(resume.FileName); err != nil || sum != e.Written {
+			return fmt.Errorf("resume.json was edited since, use --force to discard those edits")
+		}
+	}
+	if e.Before != e.After {
+		if e.Before == "" {
+			return fmt.Errorf("the first version of a branch cannot be undone")
+		}
+		mode := git.HardReset
+		if e.Action == "commit" || e.Action == "amend" {
+			// Keep the committed edits in the working file
+			mode = git.MixedReset
+		} else if dirty, _ := resumeDirty(w); dirty && e.Written == "" {
+			return fmt.Errorf("resume.json has unsaved changes, commit them first")
+		}
+		if err := w.Reset(&git.ResetOptions{Commit: plumbing.NewHash(e.Before), Mode: mode}); err != nil {
+			return err
+		}
+	}
+	if e.Written != "" {
+		return os.WriteFile(resume.FileName, []byte(e.Resume), 0644)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+
+	"mycelium/resume"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// testRepo creates a network in a temporary working directory with one
+// version per message. Each version carries its message as the name, so
+// every version differs from the last.
+func testRepo(t *testing.T, messages ...string) (*git.Repository, []plumbing.Hash) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	r, err := git.PlainInit(".", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var hashes []plumbing.Hash
+	for _, msg := range messages {
+		hashes = append(hashes, commitVersion(t, r, msg))
+	}
+	return r, hashes
+}
+
+func commitVersion(t *testing.T, r *git.Repository, name string) plumbing.Hash {
+	t.Helper()
+	writeName(t, name)
+	w, _ := r.Worktree()
+	if _, err := w.Add(resume.FileName); err != nil {
+		t.Fatal(err)
+	}
+	hash, err := w.Commit(name, &git.CommitOptions{Author: signature()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return hash
+}
+
+func writeName(t *testing.T, name string) {
+	t.Helper()
+	res := resume.Default()
+	res.Basics.Name = name
+	if err := res.Save(resume.FileName); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func workingName(t *testing.T) string {
+	t.Helper()
+	res, err := resume.Load(resume.FileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res.Basics.Name
+}
+
+func lastEntry(t *testing.T) journalEntry {
+	t.Helper()
+	entries, err := loadJournal()
+	if err != nil || len(entries) == 0 {
+		t.Fatalf("journal = %v, %v", entries, err)
+	}
+	return entries[len(entries)-1]
+}
+
+func TestJournalRoundTrip(t *testing.T) {
+	testRepo(t)
+	for i := 0; i < journalSize+5; i++ {
+		record(journalEntry{Action: "commit", Summary: fmt.Sprint(i), Before: "a", After: "b"})
+	}
+	entries, err := loadJournal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != journalSize {
+		t.Fatalf("journal holds %d entries, want %d", len(entries), journalSize)
+	}
+	first, last := entries[0], entries[len(entries)-1]
+	if first.Summary != "5" || last.Summary != fmt.Sprint(journalSize+4) {
+		t.Errorf("journal kept %s..%s, want the newest entries", first.Summary, last.Summary)
+	}
+	if last.Action != "commit" || last.Before != "a" || last.After != "b" || last.When.IsZero() {
+		t.Errorf("entry did not round-trip: %+v", last)
+	}
+}
+
+func TestUndoRestore(t *testing.T) {
+	r, hashes := testRepo(t, "v1", "v2")
+	old, _ := r.CommitObject(hashes[0])
+	res, _ := readResumeAt(old)
+	data, _ := resume.Marshal(res)
+
+	if err := writeRestored(r, data, "restored", "Restore v1", false); err != nil {
+		t.Fatal(err)
+	}
+	if name := workingName(t); name != "v1" {
+		t.Fatalf("restored name = %s, want v1", name)
+	}
+	if err := undo(r, lastEntry(t), false); err != nil {
+		t.Fatal(err)
+	}
+	if name := workingName(t); name != "v2" {
+		t.Errorf("name after undo = %s, want v2", name)
+	}
+
+	// Undo refuses to throw away edits made after the restore
+	if err := writeRestored(r, data, "restored", "Restore v1", false); err != nil {
+		t.Fatal(err)
+	}
+	writeName(t, "edited")
+	if err := undo(r, lastEntry(t), false); err == nil {
+		t.Error("undo discarded later edits")
+	}
+	if err := undo(r, lastEntry(t), true); err != nil {
+		t.Fatal(err)
+	}
+	if name := workingName(t); name != "v2" {
+		t.Errorf("name after forced undo = %s, want v2", name)
+	}
+}
+
+func TestUndoRestoreCommit(t *testing.T) {
+	r, hashes := testRepo(t, "v1", "v2")
+	old, _ := r.CommitObject(hashes[0])
+	res, _ := readResumeAt(old)
+	data, _ := resume.Marshal(res)
+
+	if err := writeRestored(r, data, "restored", "Restore v1", true); err != nil {
+		t.Fatal(err)
+	}
+	if head := headHash(r); head == hashes[1].String() {
+		t.Fatal("restore --commit did not make a version")
+	}
+	if err := undo(r, lastEntry(t), false); err != nil {
+		t.Fatal(err)
+	}
+	if head := headHash(r); head != hashes[1].String() {
+		t.Errorf("HEAD after undo = %s, want v2", head)
+	}
+	if name := workingName(t); name != "v2" {
+		t.Errorf("name after undo = %s, want v2", name)
+	}
+}
+
+func TestUndoSquash(t *testing.T) {
+	r, hashes := testRepo(t, "v1", "v2", "v3", "v4")
+	squashCmd.Run(squashCmd, []string{hashes[0].String() + ".." + hashes[2].String()})
+	if head := headHash(r); head == hashes[3].String() {
+		t.Fatal("squash did not rewrite the branch")
+	}
+	if e := lastEntry(t); e.Action != "squash" {
+		t.Fatalf("last journal entry = %s, want squash", e.Action)
+	}
+	if err := undo(r, lastEntry(t), false); err != nil {
+		t.Fatal(err)
+	}
+	if head := headHash(r); head != hashes[3].String() {
+		t.Errorf("HEAD after undo = %s, want v4", head)
+	}
+	if name := workingName(t); name != "v4" {
+		t.Errorf("name after undo = %s, want v4", name)
+	}
+}
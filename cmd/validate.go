@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"mycelium/resume"
+
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	rootCmd.AddCommand(validateCmd)
+	validateCmd.Flags().Bool("schema", false, "Print the JSON Schema resumes are validated against")
+}
+
+var validateCmd = &cobra.Command{
+	Use:   "validate [file]",
+	Short: "Check resume.json against the Mycelium schema",
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if printSchema, _ := cmd.Flags().GetBool("schema"); printSchema {
+			os.Stdout.Write(resume.SchemaJSON)
+			return
+		}
+
+		path := resume.FileName
+		if len(args) == 1 {
+			path = args[0]
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Printf("[ERROR] Could not read %s: %v\n", path, err)
+			os.Exit(1)
+		}
+
+		if !reportViolations(path, data) {
+			os.Exit(1)
+		}
+		fmt.Printf("[SUCCESS] %s is valid (schema v%d).\n", path, resume.SchemaVersion)
+	},
+}
+
+// reportViolations prints every schema violation in data and reports
+// whether the file is valid.
+func reportViolations(path string, data []byte) bool {
+	violations := resume.Validate(data)
+	if len(violations) == 0 {
+		return true
+	}
+	fmt.Printf("[ERROR] %s has %d schema violation(s) (schema v%d):\n", path, len(violations), resume.SchemaVersion)
+	for _, v := range violations {
+		fmt.Printf("  %s:%s\n", path, v)
+	}
+	return false
+}
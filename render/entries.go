@@ -0,0 +1,84 @@
+package render
+
+import (
+	"strings"
+
+	"mycelium/resume"
+)
+
+// entry is one item of a section flattened for the text formats, which
+// lay every section out the same way: a title with a subtitle, a line of
+// details, and bullets.
+type entry struct {
+	Title    string
+	Subtitle string
+	Location string
+	Date     string
+	// Note is a detail beyond place and time, such as a grade
+	Note   string
+	Link   string
+	Points []string
+}
+
+// Details returns the place, time and note of the entry that are set.
+func (e entry) Details() []string {
+	var out []string
+	for _, d := range []string{e.Location, e.Date, e.Note} {
+		if d != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// entries flattens the items of a list section. Skills are not a list of
+// entries and are left to each format.
+func entries(r *resume.Resume, section string) []entry {
+	var out []entry
+	switch section {
+	case "education":
+		for _, e := range r.Education {
+			entry := newEntry(e.School, e.Degree, e.Location, e.Date, "", nil)
+			if cgpa := strings.TrimSpace(e.CGPA); cgpa != "" {
+				entry.Note = "CGPA: " + cgpa
+			}
+			out = append(out, entry)
+		}
+	case "experience":
+		for _, e := range r.Experience {
+			out = append(out, newEntry(e.Company, e.Role, e.Location, e.Date, "", e.Points))
+		}
+	case "projects":
+		for _, p := range r.Projects {
+			out = append(out, newEntry(p.Name, p.Tech, "", p.Date, "", p.Points))
+		}
+	case "awards":
+		for _, a := range r.Awards {
+			out = append(out, newEntry(a.Title, a.Issuer, "", a.Date, "", a.Points))
+		}
+	case "publications":
+		for _, p := range r.Publications {
+			out = append(out, newEntry(p.Title, p.Publisher, "", p.Date, p.URL, nil))
+		}
+	case "volunteering":
+		for _, v := range r.Volunteering {
+			out = append(out, newEntry(v.Organization, v.Role, v.Location, v.Date, "", v.Points))
+		}
+	case "certifications":
+		for _, c := range r.Certifications {
+			out = append(out, newEntry(c.Name, c.Issuer, "", c.Date, c.URL, nil))
+		}
+	}
+	return out
+}
+
+func newEntry(title, subtitle, location, date, link string, points []string) entry {
+	return entry{
+		Title:    strings.TrimSpace(title),
+		Subtitle: strings.TrimSpace(subtitle),
+		Location: strings.TrimSpace(location),
+		Date:     strings.TrimSpace(date),
+		Link:     strings.TrimSpace(link),
+		Points:   bullets(points),
+	}
+}
@@ -0,0 +1,121 @@
+package render
+
+import (
+	"html/template"
+	"strings"
+
+	"mycelium/resume"
+)
+
+// Funcs returns the helper functions every template can call:
+//
+//	sections .            the sections to print, see Sections
+//	contact .Basics       the non-empty contact details, in order
+//	link "github.com/x"   a URL for a contact detail (https://, mailto:)
+//	join ", " .List       strings.Join with the separator first
+//	items .Items          a comma separated skill list, split and trimmed
+//	bullets .Points       the bullets that are not blank
+//	upper, lower          change case
+func Funcs() template.FuncMap {
+	return template.FuncMap{
+		"sections": Sections,
+		"contact":  contact,
+		"link":     link,
+		"join":     func(sep string, list []string) string { return strings.Join(list, sep) },
+		"items":    items,
+		"bullets":  bullets,
+		"upper":    strings.ToUpper,
+		"lower":    strings.ToLower,
+	}
+}
+
+func contact(b resume.Basics) []string {
+	var out []string
+	for _, v := range []string{b.Phone, b.Email, b.LinkedIn, b.GitHub} {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// link makes a contact detail clickable: e-mail addresses get mailto:,
+// phone numbers tel:, and bare domains https://. Only web links are passed
+// through as written; anything else with a scheme goes nowhere.
+func link(s string) template.URL {
+	s = strings.TrimSpace(s)
+	lower := strings.ToLower(s)
+	switch {
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://"):
+		return template.URL(s)
+	case strings.Contains(s, ":"):
+		return "#"
+	case strings.Contains(s, "@"):
+		return template.URL("mailto:" + s)
+	case strings.Trim(s, "+0123456789-() .") == "":
+		return template.URL("tel:" + strings.NewReplacer(" ", "", "(", "", ")", "", ".", "").Replace(s))
+	}
+	return template.URL("https://" + s)
+}
+
+func items(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+func bullets(points []string) []string {
+	var out []string
+	for _, p := range points {
+		if strings.TrimSpace(p) != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+// Section is one section a template should print, in resume order.
+type Section struct {
+	Key   string
+	Title string
+}
+
+// sectionTitles are the default headings of the sections the model knows.
+var sectionTitles = map[string]string{
+	"education":      "Education",
+	"skills":         "Technical Skills",
+	"experience":     "Experience",
+	"projects":       "Projects",
+	"awards":         "Awards",
+	"publications":   "Publications",
+	"volunteering":   "Volunteering",
+	"certifications": "Certifications",
+}
+
+// Sections lists the sections of r in its sectionOrder, leaving out the
+// empty ones so that no heading is printed without content.
+func Sections(r *resume.Resume) []Section {
+	var out []Section
+	for _, key := range r.Sections() {
+		if !r.Empty(key) {
+			out = append(out, Section{Key: key, Title: sectionTitles[key]})
+		}
+	}
+	return out
+}
+
+// UnknownSections returns the entries of sectionOrder that name no section
+// the model knows, and so are never rendered.
+func UnknownSections(r *resume.Resume) []string {
+	var out []string
+	for _, key := range r.Sections() {
+		if _, ok := sectionTitles[key]; !ok {
+			out = append(out, key)
+		}
+	}
+	return out
+}
@@ -0,0 +1,196 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"mycelium/resume"
+)
+
+// LaTeX writes the resume as a compilable Jake's Resume .tex source, for
+// anyone who would rather build the PDF with their own TeX toolchain.
+func LaTeX(w io.Writer, r *resume.Resume) error {
+	var b strings.Builder
+	b.WriteString(latexPreamble)
+
+	// 1. Heading
+	b.WriteString("\\begin{center}\n")
+	fmt.Fprintf(&b, "    \\textbf{\\Huge \\scshape %s} \\\\ \\vspace{1pt}\n", texEscape(strings.TrimSpace(r.Basics.Name)))
+	if details := contact(r.Basics); len(details) > 0 {
+		parts := make([]string, len(details))
+		for i, c := range details {
+			url := string(link(c))
+			if strings.HasPrefix(url, "tel:") {
+				parts[i] = texEscape(c)
+			} else {
+				parts[i] = fmt.Sprintf("\\href{%s}{\\underline{%s}}", hrefEscape(url), texEscape(c))
+			}
+		}
+		fmt.Fprintf(&b, "    \\small %s\n", strings.Join(parts, " $|$ "))
+	}
+	b.WriteString("\\end{center}\n")
+
+	// 2. Sections, as in the HTML template: the name of the entry with its
+	// date on top, the subtitle with the place (or grade) below
+	for _, s := range Sections(r) {
+		fmt.Fprintf(&b, "\n%%-----------%s-----------\n", strings.ToUpper(s.Title))
+		fmt.Fprintf(&b, "\\section{%s}\n", texEscape(s.Title))
+		if s.Key == "skills" {
+			b.WriteString(" \\begin{itemize}[leftmargin=0.15in, label={}]\n    \\small{\\item{\n")
+			for i, g := range r.Skills {
+				end := " \\\\\n"
+				if i == len(r.Skills)-1 {
+					end = "\n"
+				}
+				fmt.Fprintf(&b, "     \\textbf{%s}{: %s}%s", texEscape(g.Name), texEscape(strings.Join(items(g.Items), ", ")), end)
+			}
+			b.WriteString("    }}\n \\end{itemize}\n")
+			continue
+		}
+
+		b.WriteString("  \\resumeSubHeadingListStart\n")
+		for _, e := range entries(r, s.Key) {
+			title := texEscape(e.Title)
+			if e.Link != "" {
+				title = fmt.Sprintf("\\href{%s}{%s}", hrefEscape(string(link(e.Link))), title)
+			}
+			if twoLineSections[s.Key] {
+				// The location keeps the right-hand slot, so a note such
+				// as the CGPA follows the degree instead
+				subtitle := e.Subtitle
+				switch {
+				case subtitle == "":
+					subtitle = e.Note
+				case e.Note != "":
+					subtitle += ", " + e.Note
+				}
+				fmt.Fprintf(&b, "    \\resumeSubheading\n      {%s}{%s}\n      {%s}{%s}\n", title, texEscape(e.Date), texEscape(subtitle), texEscape(e.Location))
+			} else {
+				heading := "\\textbf{" + title + "}"
+				if e.Subtitle != "" {
+					heading += " $|$ \\emph{" + texEscape(e.Subtitle) + "}"
+				}
+				fmt.Fprintf(&b, "    \\resumeProjectHeading\n      {%s}{%s}\n", heading, texEscape(e.Date))
+			}
+			if len(e.Points) > 0 {
+				b.WriteString("      \\resumeItemListStart\n")
+				for _, p := range e.Points {
+					fmt.Fprintf(&b, "        \\resumeItem{%s}\n", texEscape(strings.TrimSpace(p)))
+				}
+				b.WriteString("      \\resumeItemListEnd\n")
+			}
+		}
+		b.WriteString("  \\resumeSubHeadingListEnd\n")
+	}
+
+	b.WriteString("\n\\end{document}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// twoLineSections are laid out with \resumeSubheading; the rest of the
+// sections get the one-line \resumeProjectHeading.
+var twoLineSections = map[string]bool{"education": true, "experience": true, "volunteering": true}
+
+// texEscape makes resume text safe to typeset: every character LaTeX
+// treats specially is replaced by the command that prints it.
+var texEscape = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`, `{`, `\{`, `}`, `\}`,
+	`~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+	`<`, `\textless{}`, `>`, `\textgreater{}`, `|`, `\textbar{}`,
+).Replace
+
+// hrefEscape escapes the characters \href still reads in a URL, and &,
+// which would end a table cell in the headings.
+var hrefEscape = strings.NewReplacer(`\`, "", `%`, `\%`, `#`, `\#`, `&`, `\&`, `{`, "", `}`, "").Replace
+
+// latexPreamble is the preamble of Jake Gutierrez's resume template (MIT).
+const latexPreamble = `%-------------------------
+% Resume in Latex
+% Based off of: https://github.com/jakegut/resume
+% License : MIT
+% Generated by Mycelium
+%------------------------
+
+\documentclass[letterpaper,11pt]{article}
+
+\usepackage{latexsym}
+\usepackage[empty]{fullpage}
+\usepackage{titlesec}
+\usepackage{marvosym}
+\usepackage[usenames,dvipsnames]{color}
+\usepackage{verbatim}
+\usepackage{enumitem}
+\usepackage[hidelinks]{hyperref}
+\usepackage{fancyhdr}
+\usepackage[english]{babel}
+\usepackage{tabularx}
+\usepackage[T1]{fontenc}
+\usepackage[utf8]{inputenc}
+\input{glyphtounicode}
+
+\pagestyle{fancy}
+\fancyhf{}
+\fancyfoot{}
+\renewcommand{\headrulewidth}{0pt}
+\renewcommand{\footrulewidth}{0pt}
+
+% Adjust margins
+\addtolength{\oddsidemargin}{-0.5in}
+\addtolength{\evensidemargin}{-0.5in}
+\addtolength{\textwidth}{1in}
+\addtolength{\topmargin}{-.5in}
+\addtolength{\textheight}{1.0in}
+
+\urlstyle{same}
+
+\raggedbottom
+\raggedright
+\setlength{\tabcolsep}{0in}
+
+% Sections formatting
+\titleformat{\section}{
+  \vspace{-4pt}\scshape\raggedright\large
+}{}{0em}{}[\color{black}\titlerule \vspace{-5pt}]
+
+% Ensure that generate pdf is machine readable/ATS parsable
+\pdfgentounicode=1
+
+%-------------------------
+% Custom commands
+\newcommand{\resumeItem}[1]{
+  \item\small{
+    {#1 \vspace{-2pt}}
+  }
+}
+
+\newcommand{\resumeSubheading}[4]{
+  \vspace{-2pt}\item
+    \begin{tabular*}{0.97\textwidth}[t]{l@{\extracolsep{\fill}}r}
+      \textbf{#1} & #2 \\
+      \textit{\small#3} & \textit{\small #4} \\
+    \end{tabular*}\vspace{-7pt}
+}
+
+\newcommand{\resumeProjectHeading}[2]{
+    \item
+    \begin{tabular*}{0.97\textwidth}{l@{\extracolsep{\fill}}r}
+      \small#1 & #2 \\
+    \end{tabular*}\vspace{-7pt}
+}
+
+\renewcommand\labelitemii{$\vcenter{\hbox{\tiny$\bullet$}}$}
+
+\newcommand{\resumeSubHeadingListStart}{\begin{itemize}[leftmargin=0.15in, label={}]}
+\newcommand{\resumeSubHeadingListEnd}{\end{itemize}}
+\newcommand{\resumeItemListStart}{\begin{itemize}}
+\newcommand{\resumeItemListEnd}{\end{itemize}\vspace{-5pt}}
+
+%-------------------------------------------
+%%%%%%  RESUME STARTS HERE  %%%%%%%%%%%%%%%%%%%%%%%%%%%%
+
+\begin{document}
+
+`
@@ -0,0 +1,133 @@
+// Package render turns the resume model into documents. Export themes are
+// html/template files: a few are built in, and users can add their own or
+// override a built-in one by dropping a file with the same name into one
+// of the template directories.
+package render
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"mycelium/resume"
+)
+
+// DefaultTemplate is the theme used when none is chosen.
+const DefaultTemplate = "jake"
+
+// templateExt is the extension of template files, both built in and on disk.
+const templateExt = ".html"
+
+//go:embed templates/*.html
+var builtins embed.FS
+
+// Template is one export theme.
+type Template struct {
+	Name string
+	// Source is the file the template was read from, or "built-in"
+	Source string
+	text   string
+}
+
+// Dirs returns the directories user templates are read from, most
+// specific first: ./templates in the network, then ~/.mycelium/templates.
+func Dirs() []string {
+	dirs := []string{"templates"}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, ".mycelium", "templates"))
+	}
+	return dirs
+}
+
+// Templates lists every available theme by name. A user template hides a
+// built-in one, or one further down Dirs, of the same name.
+func Templates() ([]Template, error) {
+	return templates(Dirs())
+}
+
+// Find returns the theme called name, or DefaultTemplate for "".
+func Find(name string) (*Template, error) {
+	return find(name, Dirs())
+}
+
+func templates(dirs []string) ([]Template, error) {
+	found := map[string]Template{}
+	entries, err := builtins.ReadDir("templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		data, err := builtins.ReadFile("templates/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(e.Name(), templateExt)
+		found[name] = Template{Name: name, Source: "built-in", text: string(data)}
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		files, err := filepath.Glob(filepath.Join(dirs[i], "*"+templateExt))
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range files {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return nil, err
+			}
+			name := strings.TrimSuffix(filepath.Base(path), templateExt)
+			found[name] = Template{Name: name, Source: path, text: string(data)}
+		}
+	}
+
+	out := make([]Template, 0, len(found))
+	for _, t := range found {
+		out = append(out, t)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out, nil
+}
+
+func find(name string, dirs []string) (*Template, error) {
+	if name == "" {
+		name = DefaultTemplate
+	}
+	all, err := templates(dirs)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(all))
+	for i, t := range all {
+		if t.Name == name {
+			return &t, nil
+		}
+		names[i] = t.Name
+	}
+	return nil, fmt.Errorf("no template '%s' (available: %s)", name, strings.Join(names, ", "))
+}
+
+// Parse compiles the template with the helper functions, reporting errors
+// in user templates against their file.
+func (t *Template) Parse() (*template.Template, error) {
+	tmpl, err := template.New(t.Name).Funcs(Funcs()).Parse(t.text)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", t.Source, err)
+	}
+	return tmpl, nil
+}
+
+// Execute renders a resume through the template as an HTML page. The
+// editor preview and the PDF export both go through here, so what the
+// editor shows is exactly what gets exported.
+func (t *Template) Execute(w io.Writer, r *resume.Resume) error {
+	tmpl, err := t.Parse()
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, r)
+}
@@ -0,0 +1,108 @@
+package render
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mycelium/resume"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// TestGolden renders every resume in testdata through every built-in
+// template and every text format, and compares the result with
+// testdata/<resume>.<template>.html or testdata/<resume>.<format>.
+// Run 'go test ./render -update' after an intended change to the output.
+func TestGolden(t *testing.T) {
+	formats := map[string]func(io.Writer, *resume.Resume) error{
+		"md":  Markdown,
+		"txt": Text,
+		"tex": LaTeX,
+	}
+
+	inputs, err := filepath.Glob(filepath.Join("testdata", "*.json"))
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no test resumes in testdata: %v", err)
+	}
+	themes, err := templates(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, input := range inputs {
+		res, err := resume.Load(input)
+		if err != nil {
+			t.Fatalf("%s: %v", input, err)
+		}
+		base := strings.TrimSuffix(filepath.Base(input), ".json")
+		for _, theme := range themes {
+			checkGolden(t, base+"."+theme.Name+".html", func(w io.Writer) error {
+				return theme.Execute(w, res)
+			})
+		}
+		for ext, write := range formats {
+			checkGolden(t, base+"."+ext, func(w io.Writer) error {
+				return write(w, res)
+			})
+		}
+	}
+}
+
+// checkGolden compares what render writes with testdata/<golden>.
+func checkGolden(t *testing.T, golden string, render func(io.Writer) error) {
+	t.Run(golden, func(t *testing.T) {
+		var got bytes.Buffer
+		if err := render(&got); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("testdata", golden)
+		if *update {
+			if err := os.WriteFile(path, got.Bytes(), 0644); err != nil {
+				t.Fatal(err)
+			}
+			return
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("%v (run with -update to create it)", err)
+		}
+		if !bytes.Equal(got.Bytes(), want) {
+			t.Errorf("output differs from %s", path)
+		}
+	})
+}
+
+func TestFindDefault(t *testing.T) {
+	theme, err := find("", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if theme.Name != DefaultTemplate || theme.Source != "built-in" {
+		t.Errorf("find(\"\") = %s from %s, want the built-in %s", theme.Name, theme.Source, DefaultTemplate)
+	}
+	if _, err := find("no-such-theme", nil); err == nil {
+		t.Error("find of a missing template should fail")
+	}
+}
+
+func TestUserTemplateOverrides(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, DefaultTemplate+templateExt), []byte("<p>{{.Basics.Name}}</p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	theme, err := find(DefaultTemplate, []string{dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got bytes.Buffer
+	if err := theme.Execute(&got, &resume.Resume{Basics: resume.Basics{Name: "A & B"}}); err != nil {
+		t.Fatal(err)
+	}
+	if got.String() != "<p>A &amp; B</p>" {
+		t.Errorf("user template rendered %q", got.String())
+	}
+}
@@ -0,0 +1,119 @@
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"mycelium/resume"
+)
+
+// Markdown writes the resume as Markdown, for GitHub profiles and READMEs.
+func Markdown(w io.Writer, r *resume.Resume) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n", mdEscape(r.Basics.Name))
+	if details := contact(r.Basics); len(details) > 0 {
+		links := make([]string, len(details))
+		for i, c := range details {
+			links[i] = fmt.Sprintf("[%s](%s)", mdEscape(c), link(c))
+		}
+		fmt.Fprintf(&b, "\n%s\n", strings.Join(links, " · "))
+	}
+
+	for _, s := range Sections(r) {
+		fmt.Fprintf(&b, "\n## %s\n\n", mdEscape(s.Title))
+		if s.Key == "skills" {
+			for _, g := range r.Skills {
+				fmt.Fprintf(&b, "- **%s:** %s\n", mdEscape(g.Name), mdEscape(strings.Join(items(g.Items), ", ")))
+			}
+			continue
+		}
+		list := entries(r, s.Key)
+		for i, e := range list {
+			title, details := mdEscape(e.Title), e.Details()
+			if e.Link != "" {
+				title = fmt.Sprintf("[%s](%s)", title, link(e.Link))
+			}
+			// Entries with bullets get a heading, short ones a list item
+			if len(e.Points) == 0 {
+				line := "- **" + title + "**"
+				if e.Subtitle != "" {
+					line += ", " + mdEscape(e.Subtitle)
+				}
+				if len(details) > 0 {
+					line += " — " + mdEscape(strings.Join(details, " · "))
+				}
+				fmt.Fprintln(&b, line)
+				continue
+			}
+			if i > 0 && len(list[i-1].Points) == 0 {
+				fmt.Fprintln(&b)
+			}
+			heading := "### " + title
+			if e.Subtitle != "" {
+				heading += " — " + mdEscape(e.Subtitle)
+			}
+			fmt.Fprintln(&b, heading)
+			if len(details) > 0 {
+				fmt.Fprintf(&b, "*%s*\n", mdEscape(strings.Join(details, " · ")))
+			}
+			fmt.Fprintln(&b)
+			for _, p := range e.Points {
+				fmt.Fprintf(&b, "- %s\n", mdEscape(p))
+			}
+			if i < len(list)-1 {
+				fmt.Fprintln(&b)
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// mdEscape keeps resume text from being read as Markdown or HTML.
+var mdEscape = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+	"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+).Replace
+
+// Text writes the resume as plain text that applicant tracking systems
+// read reliably: no columns, no symbols, one fact per line.
+func Text(w io.Writer, r *resume.Resume) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, strings.ToUpper(strings.TrimSpace(r.Basics.Name)))
+	if details := contact(r.Basics); len(details) > 0 {
+		fmt.Fprintln(&b, strings.Join(details, " | "))
+	}
+
+	for _, s := range Sections(r) {
+		fmt.Fprintf(&b, "\n%s\n", strings.ToUpper(s.Title))
+		if s.Key == "skills" {
+			for _, g := range r.Skills {
+				fmt.Fprintf(&b, "%s: %s\n", g.Name, strings.Join(items(g.Items), ", "))
+			}
+			continue
+		}
+		for i, e := range entries(r, s.Key) {
+			if i > 0 {
+				fmt.Fprintln(&b)
+			}
+			line := e.Title
+			if e.Subtitle != "" {
+				line += " - " + e.Subtitle
+			}
+			fmt.Fprintln(&b, line)
+			details := e.Details()
+			if e.Link != "" {
+				details = append(details, e.Link)
+			}
+			if len(details) > 0 {
+				fmt.Fprintln(&b, strings.Join(details, " | "))
+			}
+			for _, p := range e.Points {
+				fmt.Fprintf(&b, "- %s\n", strings.TrimSpace(p))
+			}
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
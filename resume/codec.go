@@ -0,0 +1,344 @@
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Extra holds fields the model does not know about, keyed by JSON name.
+// They are written back where the file had them, or after the known fields
+// sorted by key.
+type Extra map[string]json.RawMessage
+
+// Skills is an ordered set of skill categories. It is stored in JSON as an
+// object ("Languages": "Go, Python") whose key order is significant.
+type Skills []SkillGroup
+
+type SkillGroup struct {
+	Name  string
+	Items string
+}
+
+// Get returns the items listed under a category.
+func (s Skills) Get(name string) (string, bool) {
+	for _, g := range s {
+		if g.Name == name {
+			return g.Items, true
+		}
+	}
+	return "", false
+}
+
+func (s *Skills) UnmarshalJSON(data []byte) error {
+	if string(bytes.TrimSpace(data)) == "null" {
+		*s = nil
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != '{' {
+		return fmt.Errorf("skills must be an object of category names to comma separated items")
+	}
+	out := Skills{}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name := tok.(string)
+		var items string
+		if err := dec.Decode(&items); err != nil {
+			return fmt.Errorf("skills.%s must be a string", name)
+		}
+		out = append(out, SkillGroup{Name: name, Items: items})
+	}
+	*s = out
+	return nil
+}
+
+func (s Skills) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, g := range s {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := encode(g.Name)
+		val, _ := encode(g.Items)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+func (r *Resume) UnmarshalJSON(data []byte) error {
+	type plain Resume
+	if err := json.Unmarshal(data, (*plain)(r)); err != nil {
+		return err
+	}
+	source, err := DecodeTree(data)
+	if err != nil {
+		return err
+	}
+	r.source = source
+	return decodeExtra(data, plain{}, &r.Extra)
+}
+
+func (r Resume) MarshalJSON() ([]byte, error) {
+	type plain Resume
+	data, err := encodeWithExtra(plain(r), r.Extra)
+	if err != nil || r.source == nil {
+		return data, err
+	}
+	tree, err := DecodeTree(data)
+	if err != nil {
+		return nil, err
+	}
+	return encode(layout(r.source, tree))
+}
+
+func (b *Basics) UnmarshalJSON(data []byte) error {
+	type plain Basics
+	if err := json.Unmarshal(data, (*plain)(b)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &b.Extra)
+}
+
+func (b Basics) MarshalJSON() ([]byte, error) {
+	type plain Basics
+	return encodeWithExtra(plain(b), b.Extra)
+}
+
+func (e *Education) UnmarshalJSON(data []byte) error {
+	type plain Education
+	if err := json.Unmarshal(data, (*plain)(e)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &e.Extra)
+}
+
+func (e Education) MarshalJSON() ([]byte, error) {
+	type plain Education
+	return encodeWithExtra(plain(e), e.Extra)
+}
+
+func (e *Experience) UnmarshalJSON(data []byte) error {
+	type plain Experience
+	if err := json.Unmarshal(data, (*plain)(e)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &e.Extra)
+}
+
+func (e Experience) MarshalJSON() ([]byte, error) {
+	type plain Experience
+	return encodeWithExtra(plain(e), e.Extra)
+}
+
+func (p *Project) UnmarshalJSON(data []byte) error {
+	type plain Project
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &p.Extra)
+}
+
+func (p Project) MarshalJSON() ([]byte, error) {
+	type plain Project
+	return encodeWithExtra(plain(p), p.Extra)
+}
+
+func (a *Award) UnmarshalJSON(data []byte) error {
+	type plain Award
+	if err := json.Unmarshal(data, (*plain)(a)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &a.Extra)
+}
+
+func (a Award) MarshalJSON() ([]byte, error) {
+	type plain Award
+	return encodeWithExtra(plain(a), a.Extra)
+}
+
+func (p *Publication) UnmarshalJSON(data []byte) error {
+	type plain Publication
+	if err := json.Unmarshal(data, (*plain)(p)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &p.Extra)
+}
+
+func (p Publication) MarshalJSON() ([]byte, error) {
+	type plain Publication
+	return encodeWithExtra(plain(p), p.Extra)
+}
+
+func (v *Volunteering) UnmarshalJSON(data []byte) error {
+	type plain Volunteering
+	if err := json.Unmarshal(data, (*plain)(v)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &v.Extra)
+}
+
+func (v Volunteering) MarshalJSON() ([]byte, error) {
+	type plain Volunteering
+	return encodeWithExtra(plain(v), v.Extra)
+}
+
+func (c *Certification) UnmarshalJSON(data []byte) error {
+	type plain Certification
+	if err := json.Unmarshal(data, (*plain)(c)); err != nil {
+		return err
+	}
+	return decodeExtra(data, plain{}, &c.Extra)
+}
+
+func (c Certification) MarshalJSON() ([]byte, error) {
+	type plain Certification
+	return encodeWithExtra(plain(c), c.Extra)
+}
+
+// decodeExtra collects every key of data that is not a field of model.
+func decodeExtra(data []byte, model interface{}, extra *Extra) error {
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for key := range knownKeys(model) {
+		delete(all, key)
+	}
+	if len(all) == 0 {
+		*extra = nil
+		return nil
+	}
+	*extra = Extra(all)
+	return nil
+}
+
+// encodeWithExtra encodes v and splices the extra fields into the object.
+func encodeWithExtra(v interface{}, extra Extra) ([]byte, error) {
+	data, err := encode(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+
+	known := knownKeys(v)
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		if !known[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for i, k := range keys {
+		if i > 0 || len(data) > 2 {
+			buf.WriteByte(',')
+		}
+		key, _ := encode(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(extra[k])
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// layout arranges the keys of cur in the order src had them and restores
+// the empty values src spelled out, such as "projects": [], which the model
+// drops. Keys new in cur follow the key that precedes them there.
+func layout(src, cur interface{}) interface{} {
+	switch c := cur.(type) {
+	case *Object:
+		s, ok := src.(*Object)
+		if !ok {
+			return cur
+		}
+		out := NewObject()
+		for _, k := range s.Keys {
+			if v, ok := c.Values[k]; ok {
+				out.Set(k, layout(s.Values[k], v))
+			} else if emptyValue(s.Values[k]) {
+				out.Set(k, s.Values[k])
+			}
+		}
+		at := 0
+		for _, k := range c.Keys {
+			if _, ok := s.Values[k]; !ok {
+				out.Keys = append(out.Keys[:at], append([]string{k}, out.Keys[at:]...)...)
+				out.Values[k] = c.Values[k]
+			}
+			for i, placed := range out.Keys {
+				if placed == k {
+					at = i + 1
+				}
+			}
+		}
+		return out
+	case []interface{}:
+		s, _ := src.([]interface{})
+		out := make([]interface{}, len(c))
+		for i, v := range c {
+			if i < len(s) {
+				out[i] = layout(s[i], v)
+			} else {
+				out[i] = v
+			}
+		}
+		return out
+	}
+	return cur
+}
+
+// emptyValue reports whether a tree value is one the model leaves out.
+func emptyValue(v interface{}) bool {
+	switch val := v.(type) {
+	case nil:
+		return true
+	case string:
+		return val == ""
+	case []interface{}:
+		return len(val) == 0
+	case *Object:
+		return len(val.Keys) == 0
+	}
+	return false
+}
+
+// encode marshals v without escaping HTML characters.
+func encode(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// knownKeys lists the JSON names of the exported fields of a struct.
+func knownKeys(v interface{}) map[string]bool {
+	t := reflect.TypeOf(v)
+	keys := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys[name] = true
+		}
+	}
+	return keys
+}
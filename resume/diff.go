@@ -0,0 +1,193 @@
+package resume
+
+// ChangeKind classifies a single semantic change.
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+	Moved    ChangeKind = "moved"
+)
+
+// Change describes one difference between two resumes. Path points into the
+// new resume, except for removals which point into the old one. For moves,
+// From and To are the old and new positions of the entry or key.
+type Change struct {
+	Kind ChangeKind
+	Path Path
+	Old  interface{}
+	New  interface{}
+	From int
+	To   int
+}
+
+// Diff compares two resumes field by field.
+func Diff(old, new *Resume) ([]Change, error) {
+	a, err := Tree(old)
+	if err != nil {
+		return nil, err
+	}
+	b, err := Tree(new)
+	if err != nil {
+		return nil, err
+	}
+	return DiffTrees(a, b), nil
+}
+
+// DiffTrees walks two generic trees and reports every addition, removal,
+// modification and reorder between them.
+func DiffTrees(old, new interface{}) []Change {
+	var changes []Change
+	diffValue(Path{}, old, new, &changes)
+	return changes
+}
+
+func diffValue(path Path, old, new interface{}, out *[]Change) {
+	switch o := old.(type) {
+	case *Object:
+		if n, ok := new.(*Object); ok {
+			diffObject(path, o, n, out)
+			return
+		}
+	case []interface{}:
+		if n, ok := new.([]interface{}); ok {
+			diffList(path, o, n, out)
+			return
+		}
+	}
+	if !Equal(old, new) {
+		*out = append(*out, Change{Kind: Modified, Path: path, Old: old, New: new})
+	}
+}
+
+func diffObject(path Path, old, new *Object, out *[]Change) {
+	for _, k := range old.Keys {
+		if _, ok := new.Get(k); !ok {
+			*out = append(*out, Change{Kind: Removed, Path: path.Key(k), Old: old.Values[k]})
+		}
+	}
+	for _, k := range new.Keys {
+		if ov, ok := old.Get(k); ok {
+			diffValue(path.Key(k), ov, new.Values[k], out)
+		} else {
+			*out = append(*out, Change{Kind: Added, Path: path.Key(k), New: new.Values[k]})
+		}
+	}
+
+	// Key order is significant for skill categories, so report reorders of
+	// the keys both sides share.
+	var oldOrder, newOrder []string
+	for _, k := range old.Keys {
+		if _, ok := new.Get(k); ok {
+			oldOrder = append(oldOrder, k)
+		}
+	}
+	for _, k := range new.Keys {
+		if _, ok := old.Get(k); ok {
+			newOrder = append(newOrder, k)
+		}
+	}
+	from := make(map[string]int, len(oldOrder))
+	for i, k := range oldOrder {
+		from[k] = i
+	}
+	seq := make([]int, len(newOrder))
+	for i, k := range newOrder {
+		seq[i] = from[k]
+	}
+	stay := longestIncreasing(seq)
+	for i, k := range newOrder {
+		if !stay[i] {
+			*out = append(*out, Change{Kind: Moved, Path: path.Key(k), Old: old.Values[k], New: new.Values[k], From: from[k], To: i})
+		}
+	}
+}
+
+// diffList pairs entries by identity rather than by position, so inserting
+// a job at the top reports one addition instead of edits to every job.
+func diffList(path Path, old, new []interface{}, out *[]Change) {
+	section := ""
+	if len(path) > 0 {
+		section = path[len(path)-1].Key
+	}
+	pairs := matchLists(section, old, new)
+
+	var moves []pair
+	for _, p := range pairs {
+		switch {
+		case p.New < 0:
+			*out = append(*out, Change{Kind: Removed, Path: path.Entry(p.Old, label(section, old, p.Old)), Old: old[p.Old]})
+		case p.Old < 0:
+			*out = append(*out, Change{Kind: Added, Path: path.Entry(p.New, label(section, new, p.New)), New: new[p.New]})
+		default:
+			moves = append(moves, p)
+			diffValue(path.Entry(p.New, label(section, new, p.New)), old[p.Old], new[p.New], out)
+		}
+	}
+
+	// Entries keep their place when their relative order is unchanged, so
+	// only report those outside the longest run still in order.
+	seq := make([]int, len(moves))
+	for i, p := range moves {
+		seq[i] = p.Old
+	}
+	stay := longestIncreasing(seq)
+	for i, p := range moves {
+		if !stay[i] {
+			*out = append(*out, Change{
+				Kind: Moved,
+				Path: path.Entry(p.New, label(section, new, p.New)),
+				Old:  old[p.Old],
+				New:  new[p.New],
+				From: p.Old,
+				To:   p.New,
+			})
+		}
+	}
+}
+
+// longestIncreasing marks the elements of seq that belong to one of its
+// longest strictly increasing subsequences. Everything else has moved.
+func longestIncreasing(seq []int) []bool {
+	n := len(seq)
+	length := make([]int, n)
+	prev := make([]int, n)
+	best := -1
+	for i := range seq {
+		length[i], prev[i] = 1, -1
+		for j := 0; j < i; j++ {
+			if seq[j] < seq[i] && length[j]+1 > length[i] {
+				length[i], prev[i] = length[j]+1, j
+			}
+		}
+		if best < 0 || length[i] > length[best] {
+			best = i
+		}
+	}
+	keep := make([]bool, n)
+	for i := best; i >= 0; i = prev[i] {
+		keep[i] = true
+	}
+	return keep
+}
+
+// MarshalJSON encodes a change for machine consumption. Old is present for
+// everything but additions and New for everything but removals.
+func (c Change) MarshalJSON() ([]byte, error) {
+	obj := NewObject()
+	obj.Set("path", c.Path.String())
+	obj.Set("pointer", c.Path.Pointer())
+	obj.Set("kind", string(c.Kind))
+	if c.Kind != Added {
+		obj.Set("old", c.Old)
+	}
+	if c.Kind != Removed {
+		obj.Set("new", c.New)
+	}
+	if c.Kind == Moved {
+		obj.Set("from", c.From)
+		obj.Set("to", c.To)
+	}
+	return obj.MarshalJSON()
+}
@@ -0,0 +1,98 @@
+package resume
+
+import (
+	"reflect"
+	"testing"
+)
+
+func decode(t *testing.T, s string) interface{} {
+	t.Helper()
+	v, err := DecodeTree([]byte(s))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// summarize reduces changes to "kind path" lines for comparison.
+func summarize(changes []Change) []string {
+	out := make([]string, len(changes))
+	for i, c := range changes {
+		out[i] = string(c.Kind) + " " + c.Path.String()
+	}
+	return out
+}
+
+func TestDiffTreesMatchesByIdentity(t *testing.T) {
+	old := decode(t, `{"experience": [
+		{"company": "Acme", "role": "Engineer", "points": ["Built things"]},
+		{"company": "Globex", "role": "Intern", "points": ["Fixed bugs"]}
+	]}`)
+	new := decode(t, `{"experience": [
+		{"company": "Initech", "role": "Lead", "points": ["Led a team"]},
+		{"company": "Acme", "role": "Engineer", "points": ["Built things", "Shipped more"]},
+		{"company": "Globex", "role": "Intern", "points": ["Fixed bugs"]}
+	]}`)
+	got := summarize(DiffTrees(old, new))
+	want := []string{
+		"added experience[Initech]",
+		"added experience[Acme].points[1]",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %q, want %q", got, want)
+	}
+}
+
+func TestDiffTreesMatchesRenameBySimilarity(t *testing.T) {
+	old := decode(t, `{"projects": [
+		{"name": "Mycelium resume tool", "points": ["Versioned resumes with git", "Semantic diffs"]}
+	]}`)
+	new := decode(t, `{"projects": [
+		{"name": "Mycelium resume manager", "points": ["Versioned resumes with git", "Semantic diffs"]}
+	]}`)
+	changes := DiffTrees(old, new)
+	got := summarize(changes)
+	want := []string{"modified projects[Mycelium resume manager].name"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %q, want %q", got, want)
+	}
+	if changes[0].Old != "Mycelium resume tool" {
+		t.Errorf("old value = %v", changes[0].Old)
+	}
+}
+
+func TestMatchListsPairsUnrelatedEntriesAsNew(t *testing.T) {
+	old := decode(t, `["Cut cloud costs by 40%"]`).([]interface{})
+	new := decode(t, `["Mentored four junior engineers"]`).([]interface{})
+	got := matchLists("points", old, new)
+	for _, p := range got {
+		if p.Old >= 0 && p.New >= 0 {
+			t.Fatalf("unrelated entries matched: %+v", got)
+		}
+	}
+	if len(got) != 2 {
+		t.Fatalf("pairs = %+v, want one removal and one addition", got)
+	}
+}
+
+func TestDiffTreesReportsOnlyMovedEntries(t *testing.T) {
+	old := decode(t, `{"skills": ["Go", "Rust", "SQL", "Docker", "Linux"]}`)
+	new := decode(t, `{"skills": ["Go", "SQL", "Docker", "Rust", "Linux"]}`)
+	changes := DiffTrees(old, new)
+	got := summarize(changes)
+	want := []string{"moved skills[3]"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("changes = %q, want %q", got, want)
+	}
+	if changes[0].From != 1 || changes[0].To != 3 {
+		t.Errorf("move = %d -> %d, want 1 -> 3", changes[0].From, changes[0].To)
+	}
+}
+
+func TestLongestIncreasing(t *testing.T) {
+	got := longestIncreasing([]int{0, 2, 3, 1, 4})
+	want := []bool{true, true, true, false, true}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("longestIncreasing = %v, want %v", got, want)
+	}
+}
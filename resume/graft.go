@@ -0,0 +1,160 @@
+package resume
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Graft returns a copy of base in which the values at the selected paths,
+// and everything below them, are taken from target. Paths address entries
+// the way Diff reports them: by their position and label in target, or in
+// base for entries target no longer has. A selected value that target
+// lacks is removed. A label shared by several entries names none of them,
+// so it is an error; such entries have to be selected by position.
+func Graft(base, target interface{}, selected []Path) (interface{}, error) {
+	for _, p := range selected {
+		if err := CheckLabels(p, base, target); err != nil {
+			return nil, err
+		}
+	}
+	return graftValue(Path{}, base, target, selected), nil
+}
+
+// CheckLabels follows p through each tree and fails if one of its labels
+// matches more than one entry of a list, e.g. two jobs at Acme.
+func CheckLabels(p Path, trees ...interface{}) error {
+	for _, tree := range trees {
+		v := tree
+	walk:
+		for i, s := range p {
+			switch val := v.(type) {
+			case *Object:
+				if s.IsIndex() {
+					break walk
+				}
+				v = val.Values[s.Key]
+			case []interface{}:
+				if !s.IsIndex() {
+					break walk
+				}
+				if s.Label == "" {
+					if s.Index < 0 || s.Index >= len(val) {
+						break walk
+					}
+					v = val[s.Index]
+					continue
+				}
+				section := ""
+				if i > 0 {
+					section = p[i-1].Key
+				}
+				var found []int
+				for j := range val {
+					if strings.EqualFold(labelOf(section, val[j]), s.Label) {
+						found = append(found, j)
+					}
+				}
+				if len(found) == 0 {
+					break walk
+				}
+				if len(found) > 1 {
+					alternatives := make([]string, len(found))
+					for k, j := range found {
+						alternatives[k] = p[:i].Index(j).String()
+					}
+					return fmt.Errorf("ambiguous label %q in %s, use %s", s.Label, p[:i], strings.Join(alternatives, " or "))
+				}
+				v = val[found[0]]
+			default:
+				break walk
+			}
+		}
+	}
+	return nil
+}
+
+func graftValue(path Path, base, target interface{}, selected []Path) interface{} {
+	leadsTo := false
+	for _, s := range selected {
+		if path.HasPrefix(s) {
+			return Clone(target)
+		}
+		if path.Overlaps(s) {
+			leadsTo = true
+		}
+	}
+	if !leadsTo {
+		return Clone(base)
+	}
+
+	switch b := base.(type) {
+	case *Object:
+		if t, ok := target.(*Object); ok {
+			return graftObject(path, b, t, selected)
+		}
+	case []interface{}:
+		if t, ok := target.([]interface{}); ok {
+			return graftList(path, b, t, selected)
+		}
+	}
+	return Clone(base)
+}
+
+func graftObject(path Path, base, target *Object, selected []Path) *Object {
+	keys := append([]string(nil), base.Keys...)
+	for _, k := range target.Keys {
+		if _, ok := base.Get(k); !ok {
+			keys = append(keys, k)
+		}
+	}
+	out := NewObject()
+	for _, k := range keys {
+		if v := graftValue(path.Key(k), base.Values[k], target.Values[k], selected); v != nil {
+			out.Set(k, v)
+		}
+	}
+	return out
+}
+
+// graftList keeps base's order. Grafted additions are placed after the
+// entry that precedes them in target.
+func graftList(path Path, base, target []interface{}, selected []Path) []interface{} {
+	section := ""
+	if len(path) > 0 {
+		section = path[len(path)-1].Key
+	}
+	kept := make([]interface{}, len(base))
+	newToOld := make([]int, len(target))
+	for _, p := range matchLists(section, base, target) {
+		switch {
+		case p.New < 0:
+			kept[p.Old] = graftValue(path.Entry(p.Old, label(section, base, p.Old)), base[p.Old], nil, selected)
+		case p.Old < 0:
+			newToOld[p.New] = -1
+		default:
+			newToOld[p.New] = p.Old
+			kept[p.Old] = graftValue(path.Entry(p.New, label(section, target, p.New)), base[p.Old], target[p.New], selected)
+		}
+	}
+
+	added := map[int][]interface{}{}
+	prev := -1
+	for i, j := range newToOld {
+		if j >= 0 {
+			prev = j
+			continue
+		}
+		if v := graftValue(path.Entry(i, label(section, target, i)), nil, target[i], selected); v != nil {
+			added[prev] = append(added[prev], v)
+		}
+	}
+
+	out := append([]interface{}{}, added[-1]...)
+	for j, v := range kept {
+		if v != nil {
+			out = append(out, v)
+		}
+		out = append(out, added[j]...)
+	}
+	return out
+}
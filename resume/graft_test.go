@@ -0,0 +1,172 @@
+package resume
+
+import "testing"
+
+func paths(t *testing.T, raw ...string) []Path {
+	t.Helper()
+	out := make([]Path, len(raw))
+	for i, s := range raw {
+		p, err := ParsePath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out[i] = p
+	}
+	return out
+}
+
+func TestGraft(t *testing.T) {
+	tests := []struct {
+		name   string
+		base   string
+		target string
+		paths  []string
+		want   string
+	}{
+		{
+			name:   "takes only the selected field",
+			base:   `{"basics": {"name": "Jane", "email": "jane@old.com"}, "skills": {"Languages": "Go"}}`,
+			target: `{"basics": {"name": "Jane Roe", "email": "jane@new.com"}, "skills": {"Languages": "Go, Rust"}}`,
+			paths:  []string{"basics.email"},
+			want:   `{"basics":{"name":"Jane","email":"jane@new.com"},"skills":{"Languages":"Go"}}`,
+		},
+		{
+			name:   "finds the entry by label wherever it is",
+			base:   `{"experience": [{"company": "Globex", "points": ["a"]}, {"company": "Acme", "points": ["b"]}]}`,
+			target: `{"experience": [{"company": "Acme", "points": ["b", "c"]}, {"company": "Globex", "points": ["z"]}]}`,
+			paths:  []string{"experience[Acme].points"},
+			want:   `{"experience":[{"company":"Globex","points":["a"]},{"company":"Acme","points":["b","c"]}]}`,
+		},
+		{
+			name:   "adds a selected entry after its neighbour",
+			base:   `{"projects": [{"name": "One"}, {"name": "Three"}]}`,
+			target: `{"projects": [{"name": "One"}, {"name": "Two"}, {"name": "Three"}, {"name": "Four"}]}`,
+			paths:  []string{"projects[Two]"},
+			want:   `{"projects":[{"name":"One"},{"name":"Two"},{"name":"Three"}]}`,
+		},
+		{
+			name:   "removes a selected entry the target lacks",
+			base:   `{"projects": [{"name": "One"}, {"name": "Two"}]}`,
+			target: `{"projects": [{"name": "Two"}]}`,
+			paths:  []string{"projects[One]"},
+			want:   `{"projects":[{"name":"Two"}]}`,
+		},
+		{
+			name:   "skips a bullet whose entry the base removed",
+			base:   `{"experience": [{"company": "Globex", "points": ["a"]}]}`,
+			target: `{"experience": [{"company": "Acme", "points": ["b", "c"]}, {"company": "Globex", "points": ["a"]}]}`,
+			paths:  []string{"experience[Acme].points[1]"},
+			want:   `{"experience":[{"company":"Globex","points":["a"]}]}`,
+		},
+		{
+			name:   "brings back a whole entry the base removed",
+			base:   `{"experience": [{"company": "Globex", "points": ["a"]}]}`,
+			target: `{"experience": [{"company": "Acme", "points": ["b", "c"]}, {"company": "Globex", "points": ["a"]}]}`,
+			paths:  []string{"experience[Acme]"},
+			want:   `{"experience":[{"company":"Acme","points":["b","c"]},{"company":"Globex","points":["a"]}]}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Graft(decode(t, tt.base), decode(t, tt.target), paths(t, tt.paths...))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s := compact(t, got); s != tt.want {
+				t.Errorf("Graft =\n%s\nwant\n%s", s, tt.want)
+			}
+		})
+	}
+}
+
+// A graft is replayed on another branch the way pick does it: as a merge
+// of the branch and the grafted tree over their common base.
+func TestGraftConflictsWithBranchEdits(t *testing.T) {
+	base := decode(t, `{"experience": [{"company": "Acme", "role": "Engineer", "points": ["Built the billing system"]}]}`)
+	source := decode(t, `{"experience": [{"company": "Acme", "role": "Staff Engineer", "points": ["Built the billing system", "Led the migration"]}]}`)
+	branch := decode(t, `{"experience": [{"company": "Acme", "role": "Senior Engineer", "points": ["Built the billing system"]}]}`)
+
+	picked, err := Graft(base, source, paths(t, "experience[Acme].role"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, conflicts := MergeTrees(base, branch, picked, nil)
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want one", conflicts)
+	}
+	c := conflicts[0]
+	if c.Path.String() != "experience[Acme].role" || c.Ours != "Senior Engineer" || c.Theirs != "Staff Engineer" {
+		t.Errorf("conflict = %s ours=%v theirs=%v", c.Path, c.Ours, c.Theirs)
+	}
+
+	picked, err = Graft(base, source, paths(t, "experience[Acme].points"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	merged, conflicts := MergeTrees(base, branch, picked, nil)
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	want := `{"experience":[{"company":"Acme","role":"Senior Engineer","points":["Built the billing system","Led the migration"]}]}`
+	if s := compact(t, merged); s != want {
+		t.Errorf("merged =\n%s\nwant\n%s", s, want)
+	}
+}
+
+// Partial restore grafts an older version into the working resume, so
+// edits outside the restored paths survive.
+func TestGraftRestoresPartOfAnOlderVersion(t *testing.T) {
+	current := decode(t, `{"basics": {"name": "Jane Roe"}, "experience": [
+		{"company": "Acme", "points": ["Rewrote the billing system"]},
+		{"company": "Globex", "points": ["Fixed bugs in the parser"]}
+	], "projects": [{"name": "Mycelium"}]}`)
+	old := decode(t, `{"basics": {"name": "Jane"}, "experience": [
+		{"company": "Globex", "points": ["Fixed bugs"]},
+		{"company": "Acme", "points": ["Built the billing system", "Cut cloud costs by 40%."]}
+	], "projects": [{"name": "Crawler"}, {"name": "Mycelium"}]}`)
+
+	tests := []struct {
+		paths []string
+		want  string
+	}{
+		{
+			[]string{"experience[Acme]"},
+			`{"basics":{"name":"Jane Roe"},"experience":[` +
+				`{"company":"Acme","points":["Built the billing system","Cut cloud costs by 40%."]},` +
+				`{"company":"Globex","points":["Fixed bugs in the parser"]}],"projects":[{"name":"Mycelium"}]}`,
+		},
+		{
+			[]string{"projects"},
+			`{"basics":{"name":"Jane Roe"},"experience":[` +
+				`{"company":"Acme","points":["Rewrote the billing system"]},` +
+				`{"company":"Globex","points":["Fixed bugs in the parser"]}],"projects":[{"name":"Crawler"},{"name":"Mycelium"}]}`,
+		},
+	}
+	for _, tt := range tests {
+		got, err := Graft(current, old, paths(t, tt.paths...))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if s := compact(t, got); s != tt.want {
+			t.Errorf("restore %v =\n%s\nwant\n%s", tt.paths, s, tt.want)
+		}
+	}
+}
+
+func TestGraftRejectsAmbiguousLabels(t *testing.T) {
+	current := decode(t, `{"experience": [
+		{"company": "Acme", "role": "Engineer", "points": ["a"]},
+		{"company": "Globex", "role": "Intern", "points": ["b"]},
+		{"company": "Acme", "role": "Intern", "points": ["c"]}
+	]}`)
+	old := decode(t, `{"experience": []}`)
+
+	_, err := Graft(current, old, paths(t, "experience[acme].points"))
+	want := `ambiguous label "acme" in experience, use experience[0] or experience[2]`
+	if err == nil || err.Error() != want {
+		t.Fatalf("err = %v, want %s", err, want)
+	}
+	if _, err := Graft(current, old, paths(t, "experience[Globex]")); err != nil {
+		t.Fatalf("unique label rejected: %v", err)
+	}
+}
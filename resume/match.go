@@ -0,0 +1,263 @@
+package resume
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// identityFields lists, per section, the fields that identify an entry.
+// The first field also labels the entry in paths, e.g. experience[Acme].
+var identityFields = map[string][]string{
+	"experience": {"company", "role"},
+	"education":  {"school", "degree"},
+	"projects":   {"name"},
+
+	"awards":         {"title"},
+	"publications":   {"title"},
+	"volunteering":   {"organization", "role"},
+	"certifications": {"name", "issuer"},
+}
+
+// fallbackIdentity is tried, in order, for lists of objects in sections
+// the model does not know about.
+var fallbackIdentity = []string{"name", "title", "company", "school"}
+
+// matchThreshold is the similarity above which two entries with different
+// identities are still treated as the same entry that was edited.
+const matchThreshold = 0.5
+
+// pair links an entry of the old list to its counterpart in the new list.
+// Either side is -1 when the entry was added or removed.
+type pair struct {
+	Old, New int
+}
+
+// matchLists pairs the entries of two versions of the list stored under
+// section: first by identity, then by similarity for what is left over.
+// Pairs are returned in new-list order followed by removed entries.
+func matchLists(section string, old, new []interface{}) []pair {
+	oldTaken := make([]bool, len(old))
+	newMatch := make([]int, len(new))
+	for i := range newMatch {
+		newMatch[i] = -1
+	}
+
+	// 1. Exact identity
+	for i, n := range new {
+		key := identity(section, n)
+		for j, o := range old {
+			if !oldTaken[j] && identity(section, o) == key {
+				oldTaken[j], newMatch[i] = true, j
+				break
+			}
+		}
+	}
+
+	// 2. Fuzzy fallback, best scores first
+	type candidate struct {
+		old, new int
+		score    float64
+	}
+	var candidates []candidate
+	for i, n := range new {
+		if newMatch[i] >= 0 {
+			continue
+		}
+		for j, o := range old {
+			if oldTaken[j] {
+				continue
+			}
+			if score := similarity(section, o, n); score >= matchThreshold {
+				candidates = append(candidates, candidate{j, i, score})
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(a, b int) bool { return candidates[a].score > candidates[b].score })
+	for _, c := range candidates {
+		if !oldTaken[c.old] && newMatch[c.new] < 0 {
+			oldTaken[c.old], newMatch[c.new] = true, c.old
+		}
+	}
+
+	pairs := make([]pair, 0, len(new)+len(old))
+	for i, j := range newMatch {
+		pairs = append(pairs, pair{Old: j, New: i})
+	}
+	for j, taken := range oldTaken {
+		if !taken {
+			pairs = append(pairs, pair{Old: j, New: -1})
+		}
+	}
+	return pairs
+}
+
+// identity returns the normalized key an entry is matched by.
+func identity(section string, v interface{}) string {
+	obj, ok := v.(*Object)
+	if !ok {
+		return "=" + normalize(textOf(v))
+	}
+	fields := identityFieldsFor(section, obj)
+	if len(fields) == 0 {
+		return "=" + normalize(textOf(obj))
+	}
+	parts := make([]string, len(fields))
+	for i, f := range fields {
+		parts[i] = normalize(textOf(obj.Values[f]))
+	}
+	return strings.Join(parts, "|")
+}
+
+func identityFieldsFor(section string, obj *Object) []string {
+	if fields, ok := identityFields[section]; ok {
+		return fields
+	}
+	for _, f := range fallbackIdentity {
+		if _, ok := obj.Values[f].(string); ok {
+			return []string{f}
+		}
+	}
+	return nil
+}
+
+// label names a list entry for display, or returns "" when the entry has
+// no identity, its label could be mistaken for a position, or it is shared
+// with another entry of the list.
+func label(section string, list []interface{}, i int) string {
+	name := labelOf(section, list[i])
+	if name == "" || strings.ContainsAny(name, "[]") {
+		return ""
+	}
+	if _, err := strconv.Atoi(name); err == nil {
+		return ""
+	}
+	for j, other := range list {
+		if j != i && strings.EqualFold(labelOf(section, other), name) {
+			return ""
+		}
+	}
+	return name
+}
+
+func labelOf(section string, v interface{}) string {
+	obj, ok := v.(*Object)
+	if !ok {
+		return ""
+	}
+	fields := identityFieldsFor(section, obj)
+	if len(fields) == 0 {
+		return ""
+	}
+	s, _ := obj.Values[fields[0]].(string)
+	return strings.TrimSpace(s)
+}
+
+// similarity scores how alike two entries are, from 0 to 1. Objects weigh
+// their identity fields above the rest of their content.
+func similarity(section string, a, b interface{}) float64 {
+	ao, aok := a.(*Object)
+	bo, bok := b.(*Object)
+	if aok != bok {
+		return 0
+	}
+	if !aok {
+		return textSimilarity(textOf(a), textOf(b))
+	}
+	content := textSimilarity(textOf(ao), textOf(bo))
+	fields := identityFieldsFor(section, ao)
+	if len(fields) == 0 {
+		return content
+	}
+	var ai, bi []string
+	for _, f := range fields {
+		ai = append(ai, textOf(ao.Values[f]))
+		bi = append(bi, textOf(bo.Values[f]))
+	}
+	return 0.6*textSimilarity(strings.Join(ai, " "), strings.Join(bi, " ")) + 0.4*content
+}
+
+// textSimilarity is the share of words two texts have in common, in order.
+func textSimilarity(a, b string) float64 {
+	wa, wb := words(a), words(b)
+	if len(wa)+len(wb) == 0 {
+		return 1
+	}
+	return 2 * float64(len(lcs(wa, wb))) / float64(len(wa)+len(wb))
+}
+
+// textOf flattens every string in a value into one space separated text.
+func textOf(v interface{}) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case *Object:
+		parts := make([]string, 0, len(val.Keys))
+		for _, k := range val.Keys {
+			parts = append(parts, textOf(val.Values[k]))
+		}
+		return strings.Join(parts, " ")
+	case []interface{}:
+		parts := make([]string, 0, len(val))
+		for _, item := range val {
+			parts = append(parts, textOf(item))
+		}
+		return strings.Join(parts, " ")
+	case nil:
+		return ""
+	}
+	data, _ := encode(v)
+	return string(data)
+}
+
+func normalize(s string) string {
+	return strings.Join(words(s), " ")
+}
+
+// words splits text into lowercase words with surrounding punctuation
+// removed, which is what similarity is measured on.
+func words(s string) []string {
+	fields := strings.Fields(strings.ToLower(s))
+	out := fields[:0]
+	for _, f := range fields {
+		f = strings.TrimFunc(f, func(r rune) bool { return unicode.IsPunct(r) })
+		if f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// lcs returns index pairs of a longest common subsequence of a and b.
+func lcs(a, b []string) [][2]int {
+	table := make([][]int, len(a)+1)
+	for i := range table {
+		table[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				table[i][j] = table[i+1][j+1] + 1
+			} else if table[i+1][j] >= table[i][j+1] {
+				table[i][j] = table[i+1][j]
+			} else {
+				table[i][j] = table[i][j+1]
+			}
+		}
+	}
+	var out [][2]int
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, [2]int{i, j})
+			i++
+			j++
+		case table[i+1][j] >= table[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return out
+}
@@ -0,0 +1,338 @@
+package resume
+
+import "strconv"
+
+// Conflict is a field or list entry that both sides changed in different
+// ways. A nil value means that side removed it (or never had it). Entry is
+// set when the conflict covers a whole list entry, such as one bullet, so
+// that both versions can be kept side by side.
+type Conflict struct {
+	Path   Path
+	Base   interface{}
+	Ours   interface{}
+	Theirs interface{}
+	Entry  bool
+}
+
+func (c Conflict) MarshalJSON() ([]byte, error) {
+	obj := NewObject()
+	obj.Set("path", c.Path.String())
+	obj.Set("pointer", c.Path.Pointer())
+	obj.Set("entry", c.Entry)
+	obj.Set("base", c.Base)
+	obj.Set("ours", c.Ours)
+	obj.Set("theirs", c.Theirs)
+	return obj.MarshalJSON()
+}
+
+// Resolver decides a conflict. It returns the values that take the place
+// of the conflicting field: none drops it, one replaces it, and list
+// entries may keep several. A nil Resolver keeps our side.
+type Resolver func(Conflict) []interface{}
+
+// Merge performs a three-way merge of two resumes that share the ancestor
+// base, see MergeTrees.
+func Merge(base, ours, theirs *Resume, resolve Resolver) (*Resume, []Conflict, error) {
+	var trees [3]interface{}
+	for i, r := range []*Resume{base, ours, theirs} {
+		t, err := Tree(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		trees[i] = t
+	}
+	merged, conflicts := MergeTrees(trees[0], trees[1], trees[2], resolve)
+	r, err := FromTree(merged)
+	return r, conflicts, err
+}
+
+// MergeTrees performs a three-way merge of two trees that share the
+// ancestor base. Independent edits are combined, list entries are matched
+// by identity, and every field changed differently on both sides is
+// reported as a conflict and settled by resolve.
+func MergeTrees(base, ours, theirs interface{}, resolve Resolver) (interface{}, []Conflict) {
+	m := &merger{resolve: resolve}
+	merged := m.value(Path{}, base, ours, theirs, false)
+	if len(merged) == 0 {
+		return nil, m.conflicts
+	}
+	return merged[0], m.conflicts
+}
+
+type merger struct {
+	resolve   Resolver
+	conflicts []Conflict
+}
+
+// value merges one field or list entry and returns what should take its
+// place, which is empty when the field ends up removed.
+func (m *merger) value(path Path, base, ours, theirs interface{}, entry bool) []interface{} {
+	switch {
+	case Equal(ours, theirs):
+		return present(Clone(ours))
+	case Equal(base, ours):
+		return present(Clone(theirs))
+	case Equal(base, theirs):
+		return present(Clone(ours))
+	}
+
+	oo, oursObj := ours.(*Object)
+	to, theirsObj := theirs.(*Object)
+	bo, baseObj := base.(*Object)
+	if oursObj && theirsObj && (baseObj || base == nil) {
+		return []interface{}{m.object(path, bo, oo, to)}
+	}
+	ol, oursList := ours.([]interface{})
+	tl, theirsList := theirs.([]interface{})
+	bl, baseList := base.([]interface{})
+	if oursList && theirsList && (baseList || base == nil) {
+		return []interface{}{m.list(path, bl, ol, tl)}
+	}
+
+	c := Conflict{Path: path, Base: base, Ours: ours, Theirs: theirs, Entry: entry}
+	m.conflicts = append(m.conflicts, c)
+	if m.resolve == nil {
+		return present(Clone(ours))
+	}
+	return m.resolve(c)
+}
+
+func present(v interface{}) []interface{} {
+	if v == nil {
+		return nil
+	}
+	return []interface{}{v}
+}
+
+func (m *merger) object(path Path, base, ours, theirs *Object) *Object {
+	if base == nil {
+		base = NewObject()
+	}
+	order := mergeOrder(base.Keys, ours.Keys, theirs.Keys)
+
+	merged := NewObject()
+	for _, k := range order {
+		if v := m.value(path.Key(k), base.Values[k], ours.Values[k], theirs.Values[k], false); len(v) > 0 {
+			merged.Set(k, v[0])
+		}
+	}
+	return merged
+}
+
+// list aligns both sides with the base by identity, so that edits to
+// different jobs or bullets combine and additions on either side survive.
+func (m *merger) list(path Path, base, ours, theirs []interface{}) []interface{} {
+	section := ""
+	if len(path) > 0 {
+		section = path[len(path)-1].Key
+	}
+
+	// Give every entry an id: base entries keep theirs across the three
+	// versions, and entries both sides added with the same identity share.
+	oursID := make([]string, len(ours))
+	theirsID := make([]string, len(theirs))
+	for _, p := range matchLists(section, base, ours) {
+		if p.New >= 0 {
+			oursID[p.New] = entryID(p.Old, "o", p.New)
+		}
+	}
+	for _, p := range matchLists(section, base, theirs) {
+		if p.New >= 0 {
+			theirsID[p.New] = entryID(p.Old, "t", p.New)
+		}
+	}
+	var oursAdded, theirsAdded []interface{}
+	var oursAddedAt, theirsAddedAt []int
+	for i, id := range oursID {
+		if id[0] == 'o' {
+			oursAdded, oursAddedAt = append(oursAdded, ours[i]), append(oursAddedAt, i)
+		}
+	}
+	for i, id := range theirsID {
+		if id[0] == 't' {
+			theirsAdded, theirsAddedAt = append(theirsAdded, theirs[i]), append(theirsAddedAt, i)
+		}
+	}
+	for _, p := range matchLists(section, oursAdded, theirsAdded) {
+		if p.Old >= 0 && p.New >= 0 && identity(section, oursAdded[p.Old]) == identity(section, theirsAdded[p.New]) {
+			theirsID[theirsAddedAt[p.New]] = oursID[oursAddedAt[p.Old]]
+		}
+	}
+
+	pairRewrites(len(base), oursID, theirsID)
+
+	values := map[string][3]interface{}{}
+	baseIDs := make([]string, len(base))
+	for j, v := range base {
+		baseIDs[j] = entryID(j, "", 0)
+		values[baseIDs[j]] = [3]interface{}{v, nil, nil}
+	}
+	for i, id := range oursID {
+		v := values[id]
+		v[1] = ours[i]
+		values[id] = v
+	}
+	for i, id := range theirsID {
+		v := values[id]
+		v[2] = theirs[i]
+		values[id] = v
+	}
+
+	var merged []interface{}
+	for _, id := range mergeOrder(baseIDs, oursID, theirsID) {
+		v := values[id]
+		entry := path.Entry(len(merged), labelFor(section, v))
+		merged = append(merged, m.value(entry, v[0], v[1], v[2], true)...)
+	}
+	if merged == nil {
+		merged = []interface{}{}
+	}
+	return merged
+}
+
+func entryID(baseIndex int, side string, index int) string {
+	if baseIndex >= 0 {
+		return "b" + strconv.Itoa(baseIndex)
+	}
+	return side + strconv.Itoa(index)
+}
+
+// pairRewrites finds base entries both sides dropped while adding an entry
+// in their place, which happens when a bullet is reworded beyond
+// recognition. The two rewrites take over the base entry's id so that they
+// are merged against it, and conflict, instead of both being kept.
+func pairRewrites(n int, oursID, theirsID []string) {
+	inOurs := map[string]bool{}
+	for _, id := range oursID {
+		inOurs[id] = true
+	}
+	shared := map[string]bool{}
+	for _, id := range theirsID {
+		if inOurs[id] {
+			shared[id] = true
+		}
+	}
+	for j := 0; j < n; j++ {
+		id := entryID(j, "", 0)
+		if inOurs[id] || contains(theirsID, id) {
+			continue
+		}
+		o, t := rewriteAt(oursID, shared, j), rewriteAt(theirsID, shared, j)
+		if o >= 0 && t >= 0 {
+			oursID[o], theirsID[t] = id, id
+		}
+	}
+}
+
+// rewriteAt returns the first entry that only one side added between the
+// base entries around j, or -1 if that side added nothing there.
+func rewriteAt(ids []string, shared map[string]bool, j int) int {
+	found := -1
+	for i, id := range ids {
+		if id[0] == 'b' {
+			if b, _ := strconv.Atoi(id[1:]); b > j {
+				return found
+			}
+			found = -1
+			continue
+		}
+		if found < 0 && !shared[id] {
+			found = i
+		}
+	}
+	return found
+}
+
+func contains(ids []string, id string) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+// labelFor labels a merged entry from whichever version still has it.
+func labelFor(section string, v [3]interface{}) string {
+	for _, side := range []interface{}{v[1], v[2], v[0]} {
+		if side != nil {
+			return label(section, []interface{}{side}, 0)
+		}
+	}
+	return ""
+}
+
+// mergeOrder combines the orderings of two edited versions of a sequence
+// of ids. If only one side reordered the shared ids its order wins; the
+// other side's additions are placed after the id that preceded them there.
+// Ids removed on one side are kept here and dropped by the value merge.
+func mergeOrder(base, ours, theirs []string) []string {
+	skeleton, other := ours, theirs
+	if sameOrder(base, ours) && !sameOrder(base, theirs) {
+		skeleton, other = theirs, ours
+	}
+
+	result := append([]string(nil), skeleton...)
+	placed := map[string]bool{}
+	for _, id := range result {
+		placed[id] = true
+	}
+
+	insertAfter := func(anchor, id string) {
+		at := 0
+		if anchor != "" {
+			for i, r := range result {
+				if r == anchor {
+					at = i + 1
+				}
+			}
+		}
+		result = append(result[:at], append([]string{id}, result[at:]...)...)
+		placed[id] = true
+	}
+
+	anchor := ""
+	for _, id := range other {
+		if !placed[id] {
+			insertAfter(anchor, id)
+		}
+		anchor = id
+	}
+	// Ids deleted on the skeleton side still need a slot so the other side
+	// can report a conflict if it edited them.
+	anchor = ""
+	for _, id := range base {
+		if !placed[id] {
+			insertAfter(anchor, id)
+		}
+		anchor = id
+	}
+	return result
+}
+
+// sameOrder reports whether the ids shared by both sequences appear in the
+// same relative order.
+func sameOrder(a, b []string) bool {
+	inB := map[string]bool{}
+	for _, id := range b {
+		inB[id] = true
+	}
+	inA := map[string]bool{}
+	var sharedA []string
+	for _, id := range a {
+		inA[id] = true
+		if inB[id] {
+			sharedA = append(sharedA, id)
+		}
+	}
+	i := 0
+	for _, id := range b {
+		if inA[id] {
+			if sharedA[i] != id {
+				return false
+			}
+			i++
+		}
+	}
+	return true
+}
@@ -0,0 +1,78 @@
+package resume
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func compact(t *testing.T, v interface{}) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestMergeTreesCombinesDisjointEdits(t *testing.T) {
+	base := decode(t, `{"experience": [
+		{"company": "Acme", "role": "Engineer", "points": ["Built the billing system", "Cut cloud costs by 40%."]},
+		{"company": "Globex", "role": "Intern", "points": ["Fixed bugs"]}
+	]}`)
+	ours := decode(t, `{"experience": [
+		{"company": "Acme", "role": "Senior Engineer", "points": ["Built the billing system", "Cut cloud costs by 40%."]},
+		{"company": "Globex", "role": "Intern", "points": ["Fixed bugs"]}
+	]}`)
+	theirs := decode(t, `{"experience": [
+		{"company": "Acme", "role": "Engineer", "points": ["Built the billing system", "Cut cloud costs by 40%.", "Led the on-call rotation"]},
+		{"company": "Globex", "role": "Intern", "points": ["Fixed bugs in the parser"]}
+	]}`)
+
+	merged, conflicts := MergeTrees(base, ours, theirs, nil)
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	want := `{"experience":[` +
+		`{"company":"Acme","role":"Senior Engineer","points":["Built the billing system","Cut cloud costs by 40%.","Led the on-call rotation"]},` +
+		`{"company":"Globex","role":"Intern","points":["Fixed bugs in the parser"]}]}`
+	if got := compact(t, merged); got != want {
+		t.Fatalf("merged =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMergeTreesConflictsOnRewrittenEntry(t *testing.T) {
+	base := decode(t, `{"points": ["Built the billing system", "Cut cloud costs by 40%.", "Mentored interns"]}`)
+	ours := decode(t, `{"points": ["Built the billing system", "Reduced infra spend by a third", "Mentored interns"]}`)
+	theirs := decode(t, `{"points": ["Built the billing system", "Reduced infra costs by 30%.", "Mentored interns"]}`)
+
+	both := func(c Conflict) []interface{} { return []interface{}{c.Ours, c.Theirs} }
+	merged, conflicts := MergeTrees(base, ours, theirs, both)
+	if len(conflicts) != 1 {
+		t.Fatalf("conflicts = %+v, want one", conflicts)
+	}
+	c := conflicts[0]
+	if !c.Entry || c.Path.String() != "points[1]" {
+		t.Errorf("conflict at %s (entry %v), want entry points[1]", c.Path, c.Entry)
+	}
+	if c.Base != "Cut cloud costs by 40%." || c.Ours != "Reduced infra spend by a third" || c.Theirs != "Reduced infra costs by 30%." {
+		t.Errorf("conflict = %+v", c)
+	}
+	want := `{"points":["Built the billing system","Reduced infra spend by a third","Reduced infra costs by 30%.","Mentored interns"]}`
+	if got := compact(t, merged); got != want {
+		t.Fatalf("merged =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestMergeTreesSharesIdenticalRewrites(t *testing.T) {
+	base := decode(t, `{"points": ["Cut cloud costs by 40%."]}`)
+	ours := decode(t, `{"points": ["Reduced infra costs by 30%."]}`)
+	theirs := decode(t, `{"points": ["Reduced infra costs by 30%."]}`)
+
+	merged, conflicts := MergeTrees(base, ours, theirs, nil)
+	if len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts: %+v", conflicts)
+	}
+	if got, want := compact(t, merged), `{"points":["Reduced infra costs by 30%."]}`; got != want {
+		t.Fatalf("merged = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,114 @@
+package resume
+
+// Operation is one RFC 6902 JSON Patch operation.
+type Operation struct {
+	Op    string
+	Path  string
+	From  string
+	Value interface{}
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	obj := NewObject()
+	obj.Set("op", o.Op)
+	if o.From != "" || o.Op == "move" {
+		obj.Set("from", o.From)
+	}
+	obj.Set("path", o.Path)
+	if o.Op == "add" || o.Op == "replace" {
+		obj.Set("value", o.Value)
+	}
+	return obj.MarshalJSON()
+}
+
+// Patch returns JSON Patch operations that turn old into new when applied
+// in order. Lists are aligned with the same identity matching as the diff,
+// so a reordered job becomes a single move rather than a rewrite.
+func Patch(old, new interface{}) []Operation {
+	var ops []Operation
+	patchValue(Path{}, old, new, &ops)
+	return ops
+}
+
+func patchValue(path Path, old, new interface{}, ops *[]Operation) {
+	switch o := old.(type) {
+	case *Object:
+		if n, ok := new.(*Object); ok {
+			patchObject(path, o, n, ops)
+			return
+		}
+	case []interface{}:
+		if n, ok := new.([]interface{}); ok {
+			patchList(path, o, n, ops)
+			return
+		}
+	}
+	if !Equal(old, new) {
+		*ops = append(*ops, Operation{Op: "replace", Path: path.Pointer(), Value: new})
+	}
+}
+
+func patchObject(path Path, old, new *Object, ops *[]Operation) {
+	for _, k := range old.Keys {
+		if _, ok := new.Get(k); !ok {
+			*ops = append(*ops, Operation{Op: "remove", Path: path.Key(k).Pointer()})
+		}
+	}
+	for _, k := range new.Keys {
+		if ov, ok := old.Get(k); ok {
+			patchValue(path.Key(k), ov, new.Values[k], ops)
+		} else {
+			*ops = append(*ops, Operation{Op: "add", Path: path.Key(k).Pointer(), Value: new.Values[k]})
+		}
+	}
+}
+
+func patchList(path Path, old, new []interface{}, ops *[]Operation) {
+	section := ""
+	if len(path) > 0 {
+		section = path[len(path)-1].Key
+	}
+	newToOld := make([]int, len(new))
+	kept := make([]bool, len(old))
+	for _, p := range matchLists(section, old, new) {
+		if p.New >= 0 {
+			newToOld[p.New] = p.Old
+		}
+		if p.New >= 0 && p.Old >= 0 {
+			kept[p.Old] = true
+		}
+	}
+
+	// 1. Remove dropped entries from the back so indices stay valid
+	for j := len(old) - 1; j >= 0; j-- {
+		if !kept[j] {
+			*ops = append(*ops, Operation{Op: "remove", Path: path.Index(j).Pointer()})
+		}
+	}
+
+	// 2. Build the new list front to back. working holds the old index of
+	// each entry currently in the list, with -1 for inserted ones.
+	var working []int
+	for j, k := range kept {
+		if k {
+			working = append(working, j)
+		}
+	}
+	for i, j := range newToOld {
+		if j < 0 {
+			*ops = append(*ops, Operation{Op: "add", Path: path.Index(i).Pointer(), Value: new[i]})
+			working = append(working[:i], append([]int{-1}, working[i:]...)...)
+			continue
+		}
+		k := i
+		for working[k] != j {
+			k++
+		}
+		if k != i {
+			*ops = append(*ops, Operation{Op: "move", From: path.Index(k).Pointer(), Path: path.Index(i).Pointer()})
+			working = append(working[:k], working[k+1:]...)
+			working = append(working[:i], append([]int{j}, working[i:]...)...)
+		}
+		patchValue(path.Index(i), old[j], new[i], ops)
+	}
+}
@@ -0,0 +1,242 @@
+// Package resume defines the canonical resume model shared by every
+// Mycelium command. Unknown fields are preserved on every object so that a
+// file can be read and written back without losing data the model does not
+// know about yet.
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+)
+
+// FileName is the resume file tracked by a Mycelium network.
+const FileName = "resume.json"
+
+// DefaultSectionOrder is used when a resume does not define its own order.
+var DefaultSectionOrder = []string{"education", "skills", "experience", "projects"}
+
+// CustomSections are the optional sections a resume may add. Without a
+// sectionOrder they are presented after the default ones, in this order.
+var CustomSections = []string{"awards", "publications", "volunteering", "certifications"}
+
+type Resume struct {
+	Basics       Basics       `json:"basics"`
+	SectionOrder []string     `json:"sectionOrder,omitempty"`
+	Education    []Education  `json:"education,omitempty"`
+	Skills       Skills       `json:"skills,omitempty"`
+	Experience   []Experience `json:"experience,omitempty"`
+	Projects     []Project    `json:"projects,omitempty"`
+
+	Awards         []Award         `json:"awards,omitempty"`
+	Publications   []Publication   `json:"publications,omitempty"`
+	Volunteering   []Volunteering  `json:"volunteering,omitempty"`
+	Certifications []Certification `json:"certifications,omitempty"`
+
+	Extra Extra `json:"-"`
+
+	// source is the tree the resume was parsed from. Marshal follows its
+	// key order and keeps the empty values it spelled out.
+	source interface{}
+}
+
+type Basics struct {
+	Name     string `json:"name"`
+	Email    string `json:"email,omitempty"`
+	Phone    string `json:"phone,omitempty"`
+	LinkedIn string `json:"linkedin,omitempty"`
+	GitHub   string `json:"github,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Education struct {
+	School   string `json:"school"`
+	Degree   string `json:"degree,omitempty"`
+	Date     string `json:"date,omitempty"`
+	CGPA     string `json:"cgpa,omitempty"`
+	Location string `json:"location,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Experience struct {
+	Company  string   `json:"company"`
+	Role     string   `json:"role,omitempty"`
+	Location string   `json:"location,omitempty"`
+	Date     string   `json:"date,omitempty"`
+	Points   []string `json:"points,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Project struct {
+	Name   string   `json:"name"`
+	Tech   string   `json:"tech,omitempty"`
+	Date   string   `json:"date,omitempty"`
+	Points []string `json:"points,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Award struct {
+	Title  string   `json:"title"`
+	Issuer string   `json:"issuer,omitempty"`
+	Date   string   `json:"date,omitempty"`
+	Points []string `json:"points,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Publication struct {
+	Title     string `json:"title"`
+	Publisher string `json:"publisher,omitempty"`
+	Date      string `json:"date,omitempty"`
+	URL       string `json:"url,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Volunteering struct {
+	Organization string   `json:"organization"`
+	Role         string   `json:"role,omitempty"`
+	Location     string   `json:"location,omitempty"`
+	Date         string   `json:"date,omitempty"`
+	Points       []string `json:"points,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+type Certification struct {
+	Name   string `json:"name"`
+	Issuer string `json:"issuer,omitempty"`
+	Date   string `json:"date,omitempty"`
+	URL    string `json:"url,omitempty"`
+
+	Extra Extra `json:"-"`
+}
+
+// Sections returns the order in which sections should be presented. A
+// resume without its own order gets the default one, followed by any
+// custom sections it fills in.
+func (r *Resume) Sections() []string {
+	if len(r.SectionOrder) > 0 {
+		return r.SectionOrder
+	}
+	order := append([]string(nil), DefaultSectionOrder...)
+	for _, key := range CustomSections {
+		if !r.Empty(key) {
+			order = append(order, key)
+		}
+	}
+	return order
+}
+
+// Empty reports whether a section has nothing to present. Sections the
+// model does not know are always empty.
+func (r *Resume) Empty(section string) bool {
+	switch section {
+	case "education":
+		return len(r.Education) == 0
+	case "skills":
+		return len(r.Skills) == 0
+	case "experience":
+		return len(r.Experience) == 0
+	case "projects":
+		return len(r.Projects) == 0
+	case "awards":
+		return len(r.Awards) == 0
+	case "publications":
+		return len(r.Publications) == 0
+	case "volunteering":
+		return len(r.Volunteering) == 0
+	case "certifications":
+		return len(r.Certifications) == 0
+	}
+	return true
+}
+
+// Parse decodes resume JSON into the canonical model.
+func Parse(data []byte) (*Resume, error) {
+	var r Resume
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
+
+// Load reads and parses a resume file from disk.
+func Load(path string) (*Resume, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Marshal encodes a resume as indented JSON. A parsed resume keeps the
+// layout of its file; a new one is written in canonical field order. HTML
+// characters are kept as-is so "R&D" stays readable in the file.
+func Marshal(r *Resume) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Save writes a resume to disk, see Marshal.
+func (r *Resume) Save(path string) error {
+	data, err := Marshal(r)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// Default returns the John Doe resume used to seed a new network.
+func Default() *Resume {
+	return &Resume{
+		Basics: Basics{
+			Name:     "John Doe",
+			Email:    "john.doe@example.com",
+			Phone:    "+1 555-0199",
+			LinkedIn: "linkedin.com/in/johndoe",
+			GitHub:   "github.com/johndoe",
+		},
+		SectionOrder: append([]string(nil), DefaultSectionOrder...),
+		Education: []Education{{
+			School:   "University of Technology",
+			Degree:   "B.S. in Computer Science",
+			Date:     "2018 - 2022",
+			CGPA:     "3.9/4.0",
+			Location: "San Francisco, CA",
+		}},
+		Skills: Skills{
+			{Name: "Languages", Items: "Golang, Python, TypeScript, SQL"},
+			{Name: "Cloud", Items: "AWS, Docker, Kubernetes"},
+			{Name: "AI/ML", Items: "PyTorch, Scikit-Learn, OpenAI API"},
+		},
+		Experience: []Experience{{
+			Company: "Tech Solutions Inc.",
+			Role:    "Software Engineer",
+			Date:    "2022 - Present",
+			Points: []string{
+				"Led development of a high-throughput data pipeline in Go.",
+				"Reduced cloud infrastructure costs by 25% through container optimization.",
+				"Mentored junior developers on best practices for version control.",
+			},
+		}},
+		Projects: []Project{{
+			Name: "Distributed Crawler",
+			Tech: "Golang, Redis, Docker",
+			Points: []string{
+				"Built a concurrent web crawler capable of processing 10k pages/minute.",
+				"Implemented Redis-based deduplication logic to prevent redundant crawls.",
+			},
+		}},
+	}
+}
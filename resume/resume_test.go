@@ -0,0 +1,102 @@
+package resume
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// A file that departs from the canonical layout: unknown keys in between
+// known ones, out of order, and empty values written out explicitly.
+const handWritten = `{
+  "sectionOrder": [
+    "experience",
+    "education"
+  ],
+  "basics": {
+    "name": "Jane Roe",
+    "pronouns": "she/her",
+    "email": "",
+    "phone": "+1 555-0100"
+  },
+  "zeta": {
+    "b": 1,
+    "a": [
+      2.50
+    ]
+  },
+  "experience": [
+    {
+      "role": "Engineer",
+      "company": "Acme",
+      "points": []
+    }
+  ],
+  "education": [],
+  "alpha": null
+}
+`
+
+func TestLoadSaveRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, FileName)
+	for name, data := range map[string]string{
+		"hand written": handWritten,
+		"default":      mustMarshal(t, Default()),
+	} {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		r, err := Load(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if err := r.Save(path); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, _ := os.ReadFile(path)
+		if string(got) != data {
+			t.Errorf("%s: Save rewrote the file:\n%s\nwant\n%s", name, got, data)
+		}
+	}
+}
+
+func TestMarshalKeepsLayoutOfEditedResume(t *testing.T) {
+	r, err := Parse([]byte(handWritten))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Basics.GitHub = "github.com/jane"
+	r.Experience[0].Points = []string{"Shipped it"}
+
+	data, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	want := `  "basics": {
+    "name": "Jane Roe",
+    "pronouns": "she/her",
+    "email": "",
+    "phone": "+1 555-0100",
+    "github": "github.com/jane"
+  },`
+	if !strings.Contains(got, want) {
+		t.Errorf("basics not laid out as in the file:\n%s", got)
+	}
+	if !strings.Contains(got, `"points": [
+        "Shipped it"
+      ]`) {
+		t.Errorf("edited points missing:\n%s", got)
+	}
+}
+
+func mustMarshal(t *testing.T, r *Resume) string {
+	t.Helper()
+	data, err := Marshal(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -0,0 +1,77 @@
+package resume
+
+// Leaf is a single text, number or boolean of a tree and where it sits.
+type Leaf struct {
+	Path  Path
+	Value interface{}
+}
+
+// Leaves lists every scalar of a tree in document order. List entries are
+// labelled as in Diff, e.g. experience[Acme].points[0].
+func Leaves(tree interface{}) []Leaf {
+	var out []Leaf
+	collectLeaves(Path{}, tree, &out)
+	return out
+}
+
+func collectLeaves(path Path, v interface{}, out *[]Leaf) {
+	switch val := v.(type) {
+	case *Object:
+		for _, k := range val.Keys {
+			collectLeaves(path.Key(k), val.Values[k], out)
+		}
+	case []interface{}:
+		section := ""
+		if len(path) > 0 {
+			section = path[len(path)-1].Key
+		}
+		for i, item := range val {
+			collectLeaves(path.Entry(i, label(section, val, i)), item, out)
+		}
+	case nil:
+	default:
+		*out = append(*out, Leaf{Path: path, Value: val})
+	}
+}
+
+// Trace follows the leaves of new back to old. It maps the JSON pointer of
+// every leaf new kept unchanged to its pointer in old. Entries are paired
+// by identity as in Diff, so a bullet keeps its history when it moves.
+func Trace(old, new interface{}) map[string]string {
+	out := map[string]string{}
+	traceValue(Path{}, Path{}, old, new, out)
+	return out
+}
+
+func traceValue(oldPath, newPath Path, old, new interface{}, out map[string]string) {
+	switch n := new.(type) {
+	case *Object:
+		o, ok := old.(*Object)
+		if !ok {
+			return
+		}
+		for _, k := range n.Keys {
+			if ov, ok := o.Get(k); ok {
+				traceValue(oldPath.Key(k), newPath.Key(k), ov, n.Values[k], out)
+			}
+		}
+	case []interface{}:
+		o, ok := old.([]interface{})
+		if !ok {
+			return
+		}
+		section := ""
+		if len(newPath) > 0 {
+			section = newPath[len(newPath)-1].Key
+		}
+		for _, p := range matchLists(section, o, n) {
+			if p.Old >= 0 && p.New >= 0 {
+				traceValue(oldPath.Index(p.Old), newPath.Index(p.New), o[p.Old], n[p.New], out)
+			}
+		}
+	default:
+		if new != nil && Equal(old, new) {
+			out[newPath.Pointer()] = oldPath.Pointer()
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package resume
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLeaves(t *testing.T) {
+	tree := decode(t, `{"basics": {"name": "Jane"}, "experience": [{"company": "Acme", "points": ["a", "b"]}]}`)
+	var got []string
+	for _, l := range Leaves(tree) {
+		got = append(got, l.Path.String()+"="+l.Value.(string))
+	}
+	want := []string{
+		"basics.name=Jane",
+		"experience[Acme].company=Acme",
+		"experience[Acme].points[0]=a",
+		"experience[Acme].points[1]=b",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Leaves = %q, want %q", got, want)
+	}
+}
+
+func TestTraceFollowsMovedEntries(t *testing.T) {
+	old := decode(t, `{"experience": [
+		{"company": "Globex", "points": ["Fixed bugs"]},
+		{"company": "Acme", "points": ["Built the billing system", "Cut cloud costs by 40%."]}
+	]}`)
+	new := decode(t, `{"experience": [
+		{"company": "Acme", "points": ["Cut cloud costs by 40%.", "Built the billing system", "Led the migration"]},
+		{"company": "Globex", "points": ["Fixed bugs"]}
+	]}`)
+	got := Trace(old, new)
+	want := map[string]string{
+		"/experience/0/company":  "/experience/1/company",
+		"/experience/0/points/0": "/experience/1/points/1",
+		"/experience/0/points/1": "/experience/1/points/0",
+		"/experience/1/company":  "/experience/0/company",
+		"/experience/1/points/0": "/experience/0/points/0",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Trace =\n%v\nwant\n%v", got, want)
+	}
+}
+
+func TestTraceFollowsRenamedEntries(t *testing.T) {
+	old := decode(t, `{"projects": [
+		{"name": "Mycelium resume tool", "tech": "Go", "points": ["Versioned resumes with git", "Semantic diffs"]}
+	]}`)
+	new := decode(t, `{"projects": [
+		{"name": "Mycelium resume manager", "tech": "Go", "points": ["Versioned resumes with git", "Semantic diffs"]}
+	]}`)
+	got := Trace(old, new)
+	// The renamed field starts a new history; the rest of the entry keeps its own
+	want := map[string]string{
+		"/projects/0/tech":     "/projects/0/tech",
+		"/projects/0/points/0": "/projects/0/points/0",
+		"/projects/0/points/1": "/projects/0/points/1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Trace =\n%v\nwant\n%v", got, want)
+	}
+}
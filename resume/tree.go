@@ -0,0 +1,320 @@
+package resume
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Object is a JSON object that remembers the order of its keys. Together
+// with []interface{}, string, json.Number, bool and nil it forms the
+// generic tree the diff and merge engines walk.
+type Object struct {
+	Keys   []string
+	Values map[string]interface{}
+}
+
+func NewObject() *Object {
+	return &Object{Values: map[string]interface{}{}}
+}
+
+func (o *Object) Get(key string) (interface{}, bool) {
+	v, ok := o.Values[key]
+	return v, ok
+}
+
+// Set stores a value, appending the key if it is new.
+func (o *Object) Set(key string, v interface{}) {
+	if _, ok := o.Values[key]; !ok {
+		o.Keys = append(o.Keys, key)
+	}
+	o.Values[key] = v
+}
+
+func (o *Object) Delete(key string) {
+	if _, ok := o.Values[key]; !ok {
+		return
+	}
+	delete(o.Values, key)
+	for i, k := range o.Keys {
+		if k == key {
+			o.Keys = append(o.Keys[:i:i], o.Keys[i+1:]...)
+			break
+		}
+	}
+}
+
+func (o *Object) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range o.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := encode(k)
+		val, err := encode(o.Values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(val)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// Tree converts a resume into its generic ordered form.
+func Tree(r *Resume) (interface{}, error) {
+	data, err := encode(r)
+	if err != nil {
+		return nil, err
+	}
+	return DecodeTree(data)
+}
+
+// FromTree converts a generic tree back into the resume model.
+func FromTree(v interface{}) (*Resume, error) {
+	data, err := encode(v)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// DecodeTree parses JSON into the generic ordered form.
+func DecodeTree(data []byte) (interface{}, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	return decodeValue(dec)
+}
+
+func decodeValue(dec *json.Decoder) (interface{}, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := NewObject()
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.Set(key.(string), val)
+		}
+		_, err := dec.Token()
+		return obj, err
+	case json.Delim('['):
+		list := []interface{}{}
+		for dec.More() {
+			val, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, val)
+		}
+		_, err := dec.Token()
+		return list, err
+	}
+	return tok, nil
+}
+
+// Equal reports whether two tree values are identical, key order included.
+func Equal(a, b interface{}) bool {
+	switch av := a.(type) {
+	case *Object:
+		bv, ok := b.(*Object)
+		if !ok || len(av.Keys) != len(bv.Keys) {
+			return false
+		}
+		for i, k := range av.Keys {
+			if bv.Keys[i] != k || !Equal(av.Values[k], bv.Values[k]) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		bv, ok := b.([]interface{})
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !Equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
+
+// Clone returns a deep copy of a tree value.
+func Clone(v interface{}) interface{} {
+	switch val := v.(type) {
+	case *Object:
+		out := &Object{Keys: append([]string(nil), val.Keys...), Values: make(map[string]interface{}, len(val.Values))}
+		for k, item := range val.Values {
+			out.Values[k] = Clone(item)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, item := range val {
+			out[i] = Clone(item)
+		}
+		return out
+	}
+	return v
+}
+
+// Step is one hop of a Path: an object key, or a list position. List
+// entries with an identity also carry a label, such as the company name.
+type Step struct {
+	Key   string
+	Index int
+	Label string
+}
+
+func (s Step) IsIndex() bool { return s.Key == "" }
+
+// Path addresses a value inside a resume tree.
+type Path []Step
+
+func (p Path) Key(k string) Path { return p.append(Step{Key: k}) }
+
+func (p Path) Index(i int) Path { return p.append(Step{Index: i}) }
+
+// Entry addresses a list entry by position and, when it has one, by label.
+func (p Path) Entry(i int, label string) Path { return p.append(Step{Index: i, Label: label}) }
+
+func (p Path) append(s Step) Path {
+	out := make(Path, len(p), len(p)+1)
+	copy(out, p)
+	return append(out, s)
+}
+
+// String renders the path as it is written on the command line, for
+// example experience[Acme].points[1].
+func (p Path) String() string {
+	var b strings.Builder
+	for i, s := range p {
+		switch {
+		case s.IsIndex() && s.Label != "":
+			fmt.Fprintf(&b, "[%s]", s.Label)
+		case s.IsIndex():
+			fmt.Fprintf(&b, "[%d]", s.Index)
+		case strings.ContainsAny(s.Key, ".[]\" "):
+			fmt.Fprintf(&b, "[%q]", s.Key)
+		default:
+			if i > 0 {
+				b.WriteByte('.')
+			}
+			b.WriteString(s.Key)
+		}
+	}
+	return b.String()
+}
+
+// Pointer renders the path as an RFC 6901 JSON pointer.
+func (p Path) Pointer() string {
+	var b strings.Builder
+	for _, s := range p {
+		b.WriteByte('/')
+		if s.IsIndex() {
+			b.WriteString(strconv.Itoa(s.Index))
+		} else {
+			b.WriteString(escapePointer(s.Key))
+		}
+	}
+	return b.String()
+}
+
+// ParsePath reads a path as written by String, such as
+// experience[Acme].points[1], or as a JSON pointer starting with a slash.
+// A bracketed number is a position and any other bracketed name a label.
+func ParsePath(s string) (Path, error) {
+	if strings.HasPrefix(s, "/") {
+		var p Path
+		for _, part := range strings.Split(s[1:], "/") {
+			if i, err := strconv.Atoi(part); err == nil {
+				p = p.Index(i)
+				continue
+			}
+			p = p.Key(strings.NewReplacer("~1", "/", "~0", "~").Replace(part))
+		}
+		return p, nil
+	}
+
+	var p Path
+	for rest := s; rest != ""; {
+		switch {
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if strings.HasPrefix(rest, `["`) {
+				end = strings.Index(rest, `"]`) + 1
+			}
+			if end <= 1 {
+				return nil, fmt.Errorf("invalid path %q: unclosed bracket", s)
+			}
+			inner := rest[1:end]
+			if key, err := strconv.Unquote(inner); err == nil {
+				p = p.Key(key)
+			} else if i, err := strconv.Atoi(inner); err == nil {
+				p = p.Index(i)
+			} else {
+				p = p.Entry(-1, inner)
+			}
+			rest = rest[end+1:]
+		case rest[0] == '.' && len(p) > 0:
+			rest = rest[1:]
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid path %q: empty key", s)
+			}
+			p = p.Key(rest[:end])
+			rest = rest[end:]
+		}
+	}
+	return p, nil
+}
+
+// HasPrefix reports whether p equals prefix or lies below it. A labelled
+// step of prefix matches entries by label, a bare position by index.
+func (p Path) HasPrefix(prefix Path) bool {
+	return len(prefix) <= len(p) && p.Overlaps(prefix)
+}
+
+// Overlaps reports whether p lies at, below or above pattern, matching
+// steps the way HasPrefix does.
+func (p Path) Overlaps(pattern Path) bool {
+	for i := 0; i < len(p) && i < len(pattern); i++ {
+		s, t := pattern[i], p[i]
+		switch {
+		case !s.IsIndex():
+			if t.Key != s.Key {
+				return false
+			}
+		case !t.IsIndex():
+			return false
+		case s.Label != "":
+			if !strings.EqualFold(t.Label, s.Label) {
+				return false
+			}
+		case t.Index != s.Index:
+			return false
+		}
+	}
+	return true
+}
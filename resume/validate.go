@@ -0,0 +1,327 @@
+package resume
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// SchemaVersion is bumped whenever schema.json changes incompatibly.
+const SchemaVersion = 1
+
+// SchemaJSON is the JSON Schema every resume.json is validated against.
+//
+//go:embed schema.json
+var SchemaJSON []byte
+
+// Violation is a single schema error, located by JSON pointer and by the
+// line and column (both 1-based) of the offending value in the file.
+type Violation struct {
+	Pointer string
+	Line    int
+	Column  int
+	Message string
+
+	offset int
+}
+
+func (v Violation) String() string {
+	ptr := v.Pointer
+	if ptr == "" {
+		ptr = "/"
+	}
+	return fmt.Sprintf("%d:%d %s: %s", v.Line, v.Column, ptr, v.Message)
+}
+
+// Validate checks raw resume JSON against the embedded schema and returns
+// every violation found, ordered by position in the file.
+func Validate(data []byte) []Violation {
+	var doc interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(&doc); err != nil {
+		offset := int(dec.InputOffset())
+		var syntax *json.SyntaxError
+		if errors.As(err, &syntax) {
+			offset = int(syntax.Offset)
+		}
+		return []Violation{locateViolation(data, Violation{Message: "invalid JSON: " + err.Error(), offset: offset})}
+	}
+
+	root, err := loadSchema()
+	if err != nil {
+		panic("resume: embedded schema is broken: " + err.Error())
+	}
+
+	var found []Violation
+	root.validate(root, doc, "", &found)
+
+	offsets := locate(data)
+	for i, v := range found {
+		v.offset = nearestOffset(offsets, v.Pointer)
+		found[i] = locateViolation(data, v)
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].offset < found[j].offset })
+	return found
+}
+
+// schema is the subset of JSON Schema used by schema.json.
+type schema struct {
+	Ref                  string             `json:"$ref"`
+	Type                 typeList           `json:"type"`
+	Required             []string           `json:"required"`
+	Properties           map[string]*schema `json:"properties"`
+	AdditionalProperties *schema            `json:"additionalProperties"`
+	Items                *schema            `json:"items"`
+	MinLength            int                `json:"minLength"`
+	MinItems             int                `json:"minItems"`
+	UniqueItems          bool               `json:"uniqueItems"`
+	Enum                 []interface{}      `json:"enum"`
+	Pattern              string             `json:"pattern"`
+	Defs                 map[string]*schema `json:"$defs"`
+
+	// never is set for the boolean schema false.
+	never bool
+}
+
+func (s *schema) UnmarshalJSON(data []byte) error {
+	switch string(bytes.TrimSpace(data)) {
+	case "true":
+		*s = schema{}
+		return nil
+	case "false":
+		*s = schema{never: true}
+		return nil
+	}
+	type plain schema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+type typeList []string
+
+func (t *typeList) UnmarshalJSON(data []byte) error {
+	var one string
+	if err := json.Unmarshal(data, &one); err == nil {
+		*t = typeList{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(data, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+func loadSchema() (*schema, error) {
+	var s schema
+	if err := json.Unmarshal(SchemaJSON, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (s *schema) resolve(root *schema) *schema {
+	for s.Ref != "" {
+		name := strings.TrimPrefix(s.Ref, "#/$defs/")
+		def, ok := root.Defs[name]
+		if !ok {
+			panic("resume: unknown schema reference " + s.Ref)
+		}
+		s = def
+	}
+	return s
+}
+
+func (s *schema) validate(root *schema, v interface{}, ptr string, out *[]Violation) {
+	s = s.resolve(root)
+	report := func(format string, args ...interface{}) {
+		*out = append(*out, Violation{Pointer: ptr, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if s.never {
+		report("is not allowed here")
+		return
+	}
+	if len(s.Type) > 0 && !s.Type.allows(v) {
+		report("expected %s, got %s", strings.Join(s.Type, " or "), jsonType(v))
+		return
+	}
+	if len(s.Enum) > 0 {
+		allowed := false
+		for _, e := range s.Enum {
+			if fmt.Sprint(e) == fmt.Sprint(v) {
+				allowed = true
+			}
+		}
+		if !allowed {
+			report("must be one of %v", s.Enum)
+		}
+	}
+
+	switch val := v.(type) {
+	case string:
+		if utf8.RuneCountInString(val) < s.MinLength {
+			if s.MinLength == 1 {
+				report("must not be empty")
+			} else {
+				report("must be at least %d characters", s.MinLength)
+			}
+		}
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(val) {
+			report("%q is not in the expected format", val)
+		}
+	case []interface{}:
+		if len(val) < s.MinItems {
+			report("must have at least %d items", s.MinItems)
+		}
+		seen := map[string]int{}
+		for i, item := range val {
+			if s.UniqueItems {
+				key := fmt.Sprint(item)
+				if first, dup := seen[key]; dup {
+					*out = append(*out, Violation{
+						Pointer: ptr + "/" + strconv.Itoa(i),
+						Message: fmt.Sprintf("duplicates item %d", first),
+					})
+				}
+				seen[key] = i
+			}
+			if s.Items != nil {
+				s.Items.validate(root, item, ptr+"/"+strconv.Itoa(i), out)
+			}
+		}
+	case map[string]interface{}:
+		for _, name := range s.Required {
+			if _, ok := val[name]; !ok {
+				*out = append(*out, Violation{
+					Pointer: ptr + "/" + escapePointer(name),
+					Message: "is required",
+				})
+			}
+		}
+		keys := make([]string, 0, len(val))
+		for k := range val {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if prop, ok := s.Properties[k]; ok {
+				prop.validate(root, val[k], ptr+"/"+escapePointer(k), out)
+			} else if s.AdditionalProperties != nil {
+				s.AdditionalProperties.validate(root, val[k], ptr+"/"+escapePointer(k), out)
+			}
+		}
+	}
+}
+
+func (t typeList) allows(v interface{}) bool {
+	actual := jsonType(v)
+	for _, want := range t {
+		if want == actual || (want == "number" && actual == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonType(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return "integer"
+		}
+		return "number"
+	case []interface{}:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+func escapePointer(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// locate maps the JSON pointer of every value in data to its byte offset.
+func locate(data []byte) map[string]int {
+	offsets := map[string]int{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+
+	var walk func(ptr string) error
+	walk = func(ptr string) error {
+		offsets[ptr] = skipSeparators(data, int(dec.InputOffset()))
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				key, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				if err := walk(ptr + "/" + escapePointer(key.(string))); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(ptr + "/" + strconv.Itoa(i)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+		}
+		return err
+	}
+	walk("")
+	return offsets
+}
+
+func skipSeparators(data []byte, off int) int {
+	for off < len(data) && strings.IndexByte(" \t\r\n,:", data[off]) >= 0 {
+		off++
+	}
+	return off
+}
+
+// nearestOffset finds the offset of ptr, or of its closest existing parent
+// when ptr names a value that is missing from the document.
+func nearestOffset(offsets map[string]int, ptr string) int {
+	for {
+		if off, ok := offsets[ptr]; ok {
+			return off
+		}
+		i := strings.LastIndex(ptr, "/")
+		if i < 0 {
+			return 0
+		}
+		ptr = ptr[:i]
+	}
+}
+
+func locateViolation(data []byte, v Violation) Violation {
+	if v.offset > len(data) {
+		v.offset = len(data)
+	}
+	before := data[:v.offset]
+	v.Line = bytes.Count(before, []byte("\n")) + 1
+	v.Column = utf8.RuneCount(before[bytes.LastIndexByte(before, '\n')+1:]) + 1
+	return v
+}
@@ -0,0 +1,94 @@
+package resume
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateDefaultIsValid(t *testing.T) {
+	data, err := Marshal(Default())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := Validate(data); len(v) != 0 {
+		t.Fatalf("default resume has violations: %v", v)
+	}
+}
+
+func TestValidateLocatesViolations(t *testing.T) {
+	data := []byte(`{
+  "basics": {
+    "name": "Jane",
+    "email": "not an email"
+  },
+  "experience": [
+    {
+      "role": "Engineer",
+      "points": ["ok", 7]
+    }
+  ]
+}`)
+	tests := []struct {
+		pointer      string
+		line, column int
+		message      string
+	}{
+		{"/basics/email", 4, 14, "expected format"},
+		// A missing field is reported at its parent object
+		{"/experience/0/company", 7, 5, "required"},
+		{"/experience/0/points/1", 9, 24, "string"},
+	}
+
+	got := Validate(data)
+	if len(got) != len(tests) {
+		t.Fatalf("got %d violations, want %d: %v", len(got), len(tests), got)
+	}
+	for i, want := range tests {
+		v := got[i]
+		if v.Pointer != want.pointer || v.Line != want.line || v.Column != want.column {
+			t.Errorf("violation %d = %s at %d:%d, want %s at %d:%d", i, v.Pointer, v.Line, v.Column, want.pointer, want.line, want.column)
+		}
+		if !strings.Contains(v.Message, want.message) {
+			t.Errorf("violation %d message %q does not mention %q", i, v.Message, want.message)
+		}
+	}
+}
+
+func TestValidateRejectsUnknownSections(t *testing.T) {
+	data := []byte(`{
+  "basics": { "name": "Jane" },
+  "sectionOrder": ["experience", "interests", "awards"]
+}`)
+	got := Validate(data)
+	if len(got) != 1 {
+		t.Fatalf("got %d violations, want 1: %v", len(got), got)
+	}
+	v := got[0]
+	if v.Pointer != "/sectionOrder/1" || v.Line != 3 || v.Column != 34 {
+		t.Errorf("violation = %s at %d:%d, want /sectionOrder/1 at 3:34", v.Pointer, v.Line, v.Column)
+	}
+	if !strings.Contains(v.Message, "certifications") {
+		t.Errorf("message %q does not list the known sections", v.Message)
+	}
+}
+
+func TestValidateSyntaxError(t *testing.T) {
+	got := Validate([]byte("{\n  \"basics\": {\n    \"name\": \"Jane\",\n  }\n}"))
+	if len(got) != 1 {
+		t.Fatalf("got %d violations, want 1: %v", len(got), got)
+	}
+	v := got[0]
+	if !strings.HasPrefix(v.Message, "invalid JSON") {
+		t.Errorf("message = %q, want an invalid JSON report", v.Message)
+	}
+	if v.Line != 4 {
+		t.Errorf("syntax error reported on line %d, want 4", v.Line)
+	}
+}
+
+func TestViolationString(t *testing.T) {
+	v := Violation{Line: 3, Column: 5, Message: "name is required"}
+	if got := v.String(); got != "3:5 /: name is required" {
+		t.Errorf("String() = %q", got)
+	}
+}
@@ -0,0 +1,51 @@
+package resume
+
+import "strings"
+
+// SpanKind says whether a run of words was kept, inserted or deleted.
+type SpanKind int
+
+const (
+	Same SpanKind = iota
+	Inserted
+	Deleted
+)
+
+// Span is a run of consecutive words sharing the same fate in a word diff.
+type Span struct {
+	Kind SpanKind
+	Text string
+}
+
+// WordDiff aligns the words of two texts and returns the runs that were
+// kept, deleted and inserted, in reading order. Words compare exactly, so
+// a change in punctuation or case shows up as a reworded word.
+func WordDiff(old, new string) []Span {
+	a, b := strings.Fields(old), strings.Fields(new)
+	common := lcs(a, b)
+
+	var spans []Span
+	add := func(kind SpanKind, word string) {
+		if n := len(spans); n > 0 && spans[n-1].Kind == kind {
+			spans[n-1].Text += " " + word
+			return
+		}
+		spans = append(spans, Span{Kind: kind, Text: word})
+	}
+
+	i, j := 0, 0
+	for _, m := range append(common, [2]int{len(a), len(b)}) {
+		for ; i < m[0]; i++ {
+			add(Deleted, a[i])
+		}
+		for ; j < m[1]; j++ {
+			add(Inserted, b[j])
+		}
+		if m[0] < len(a) {
+			add(Same, a[i])
+			i++
+			j++
+		}
+	}
+	return spans
+}